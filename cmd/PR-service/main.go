@@ -29,13 +29,20 @@ package main
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
+	"os"
+	"time"
 
 	_ "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/docs"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/app"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/grpcapi"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/health"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/sla"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
 func main() {
@@ -43,27 +50,85 @@ func main() {
 
 	defer cancel()
 
-	app.Init() // Initialize  components (dotenv, config, metrics)
+	cfg := app.Init(os.Args[1:]) // Load configuration and initialize components (logger, metrics, assignment)
 
-	database.RunMigrations(config.PostgresURL) // Run database migrations
+	reloader := app.NewReloader(os.Args[1:], cfg)
 
-	database.InitDB(ctx, config.PostgresURL) // Initialize database connection
+	app.HandleSignals(cancel, reloader.Reload) // Cancel context on SIGINT, SIGTERM, reload config on SIGHUP
+
+	go reloader.Watch(ctx) // Reload when the config file changes
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Trace) // Install tracer provider and exporter
+
+	if err != nil {
+		logger.Fatal(err, "failed to init tracing")
+	}
+
+	database.RunMigrations(cfg.Database) // Run database migrations
+
+	database.InitDB(ctx, cfg.Database) // Initialize database connection
 
 	defer database.DB.Close()
 
-	err := app.LoadCacheFromDB(ctx) // Load data from database into cache
+	metrics.OpenReviewsSource = database.OpenReviewsByTeamFromDB // Open review gauges are read on scrape
+
+	app.InitCaches(cfg.Cache) // Empty caches, filled by warm-up
+
+	subscribed := app.StartCacheInvalidation(ctx) // Evict entries written by other instances, including during warm-up
+
+	app.StartEventRelay(ctx) // Review streams get events of every instance
+
+	e := app.StartServer(ctx, cfg) // Setup and configure HTTP server, API answers 503 until warm-up finishes
+
+	go func() {
+
+		if err := e.Start(cfg.Server.HTTPAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+
+			logger.Error(err, "failed to start server")
+
+		}
+
+	}()
+
+	select {
+
+	case <-subscribed:
+
+	case <-ctx.Done():
+
+	case <-time.After(cfg.Database.ConnectTimeout): // The listener purges the caches once it subscribes
+
+		logger.Info("cache invalidation listener not subscribed yet, warming up anyway")
+
+	}
+
+	err = app.LoadCacheFromDB(ctx, cfg.Cache) // Load data from database into cache
 
 	if err != nil {
 		logger.Error(err, "cache dont loaded") // if cache load error - work continue
 	}
 
-	e := app.StartServer(ctx) // Setup and configure HTTP server
+	health.MarkWarmedUp(err) // Readiness reports the warm-up outcome
+
+	go sla.Run(ctx, cfg.SLA) // Remind about and escalate overdue reviews, only one instance acts at a time
+
+	g := grpcapi.NewServer() // Setup gRPC server with the same services
 
 	go func() {
 
-		if err := e.Start(":8080"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		lis, err := net.Listen("tcp", cfg.Server.GRPCAddr)
 
-			logger.Error(err, "failed to start server")
+		if err != nil {
+
+			logger.Error(err, "failed to listen for gRPC")
+
+			return
+
+		}
+
+		if err := g.Serve(lis); err != nil {
+
+			logger.Error(err, "failed to start gRPC server")
 
 		}
 
@@ -71,6 +136,14 @@ func main() {
 
 	<-ctx.Done() // Wait for shutdown signal (SIGINT, SIGTERM)
 
-	app.GracefulShutdown(e, database.DB) // Graceful shutdown
+	app.GracefulShutdown(e, g, database.DB, cfg.Server.ShutdownDrain) // Graceful shutdown
+
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	defer flushCancel()
+
+	if err := shutdownTracing(flushCtx); err != nil { // Export spans still buffered
+		logger.Error(err, "failed to flush traces")
+	}
 
 }
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// client performs admin operations either over HTTP or directly against Postgres
+// Results are printed as JSON, so implementations may return API responses as is
+type client interface {
+	AddTeam(ctx context.Context, team models.Team) (interface{}, error)
+
+	GetTeam(ctx context.Context, teamName string) (interface{}, error)
+
+	ListTeams(ctx context.Context, limit int) (interface{}, error)
+
+	SetIsActive(ctx context.Context, user models.UserActivity) (interface{}, error)
+
+	CreatePR(ctx context.Context, pr models.PullRequestShort) (interface{}, error)
+
+	MergePR(ctx context.Context, pr models.PRMerge) (interface{}, error)
+
+	ReassignPR(ctx context.Context, pr models.PRReassign) (interface{}, error)
+
+	ListPRs(ctx context.Context, status string, limit int) (interface{}, error)
+
+	Stats(ctx context.Context) (interface{}, error)
+
+	Export(ctx context.Context, format string, w io.Writer) error
+
+	Import(ctx context.Context, r io.Reader, format string, policy string) (interface{}, error)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/stats"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/team"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/transfer"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/validation"
+)
+
+const maxListLimit = 1000 // same cap as the HTTP API
+
+// directClient calls the service packages against Postgres without a running server
+// Writes still notify running instances, which evict the written entries from their caches
+// and pass the review events to their /users/reviewStream subscribers
+// Requests are checked with the same rules as the HTTP API before they reach the services
+type directClient struct {
+	validator *validation.Validator
+}
+
+func newDirectClient(ctx context.Context, cfg config.Config) *directClient {
+
+	database.InitDB(ctx, cfg.Database) // Initialize database connection
+
+	cache.InitCache(cfg.Cache) // Services read through the cache, start it empty
+
+	pullrequest.Init(cfg.Assignment) // Same reviewer defaults as the service
+
+	repository.Init()
+
+	events.Default.SetRelay(database.PublishEvent) // Subscribers of running instances get the events of these writes
+
+	return &directClient{validator: validation.New()}
+
+}
+
+func (c *directClient) AddTeam(ctx context.Context, bindedTeam models.Team) (interface{}, error) {
+
+	if err := c.validator.Validate(&bindedTeam); err != nil {
+
+		return nil, err
+
+	}
+
+	return team.Add(bindedTeam, ctx)
+
+}
+
+func (c *directClient) GetTeam(ctx context.Context, teamName string) (interface{}, error) {
+
+	if err := requireField("team_name", teamName); err != nil {
+
+		return nil, err
+
+	}
+
+	return team.Get(teamName, ctx)
+
+}
+
+func (c *directClient) ListTeams(ctx context.Context, limit int) (interface{}, error) {
+
+	if err := checkLimit(limit); err != nil {
+
+		return nil, err
+
+	}
+
+	return team.List(ctx, limit)
+
+}
+
+func (c *directClient) SetIsActive(ctx context.Context, user models.UserActivity) (interface{}, error) {
+
+	if err := c.validator.Validate(&user); err != nil {
+
+		return nil, err
+
+	}
+
+	return team.SetActive(user, ctx)
+
+}
+
+func (c *directClient) CreatePR(ctx context.Context, pr models.PullRequestShort) (interface{}, error) {
+
+	if err := c.validator.Validate(&pr); err != nil {
+
+		return nil, err
+
+	}
+
+	return pullrequest.Create(ctx, pr)
+
+}
+
+func (c *directClient) MergePR(ctx context.Context, pr models.PRMerge) (interface{}, error) {
+
+	if err := c.validator.Validate(&pr); err != nil {
+
+		return nil, err
+
+	}
+
+	return pullrequest.Merge(ctx, pr)
+
+}
+
+func (c *directClient) ReassignPR(ctx context.Context, pr models.PRReassign) (interface{}, error) {
+
+	if err := c.validator.Validate(&pr); err != nil {
+
+		return nil, err
+
+	}
+
+	return pullrequest.Reassign(ctx, pr)
+
+}
+
+func (c *directClient) ListPRs(ctx context.Context, status string, limit int) (interface{}, error) {
+
+	if status != "" && status != pullrequest.OpenStatus && status != pullrequest.MergeStatus {
+
+		return nil, &errs.ValidationFailure{Details: []errs.FieldError{{Field: "status", Message: "must be OPEN or MERGED"}}}
+
+	}
+
+	if err := checkLimit(limit); err != nil {
+
+		return nil, err
+
+	}
+
+	return pullrequest.List(ctx, status, limit)
+
+}
+
+func (c *directClient) Stats(ctx context.Context) (interface{}, error) {
+
+	return stats.Get(ctx)
+
+}
+
+func (c *directClient) Export(ctx context.Context, format string, w io.Writer) error {
+
+	return transfer.Export(ctx, w, format)
+
+}
+
+func (c *directClient) Import(ctx context.Context, r io.Reader, format string, policy string) (interface{}, error) {
+
+	dump, err := transfer.Decode(r, format)
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	return transfer.Import(ctx, dump, policy)
+
+}
+
+// requireField returns a validation failure for an empty required field
+func requireField(field string, value string) error {
+
+	if strings.TrimSpace(value) == "" {
+
+		return &errs.ValidationFailure{Details: []errs.FieldError{{Field: field, Message: "is required"}}}
+
+	}
+
+	return nil
+
+}
+
+// checkLimit rejects list limits the HTTP API would reject
+func checkLimit(limit int) error {
+
+	if limit < 1 || limit > maxListLimit {
+
+		return &errs.ValidationFailure{Details: []errs.FieldError{{Field: "limit", Message: fmt.Sprintf("must be a number from 1 to %d", maxListLimit)}}}
+
+	}
+
+	return nil
+
+}
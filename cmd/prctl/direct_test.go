@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/validation"
+)
+
+// The client has no database, so any request reaching the services would fail differently
+func TestDirectClient_RejectsInvalidRequests(t *testing.T) {
+
+	ctx := context.Background()
+
+	c := &directClient{validator: validation.New()}
+
+	_, err := c.CreatePR(ctx, models.PullRequestShort{PullRequestID: "", PullRequestName: "feature", AuthorID: "u1"})
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	_, err = c.AddTeam(ctx, models.Team{TeamName: " ", Members: []models.TeamMember{{UserID: "u1", Username: "User1"}}})
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	_, err = c.GetTeam(ctx, "")
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	_, err = c.ListPRs(ctx, "CLOSED", 10)
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	_, err = c.ListTeams(ctx, 0)
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+}
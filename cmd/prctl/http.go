@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// httpClient talks to a running service through its HTTP API
+type httpClient struct {
+	baseURL string
+
+	adminToken string // sent as a bearer token, /admin/* rejects requests without it
+
+	http *http.Client
+}
+
+func newHTTPClient(baseURL string, adminToken string) *httpClient {
+
+	return &httpClient{baseURL: baseURL, adminToken: adminToken, http: &http.Client{}} // Requests are bounded by the -timeout context
+
+}
+
+// do sends a JSON or raw request and decodes a JSON response, API errors are returned as Go errors
+func (c *httpClient) do(ctx context.Context, method string, path string, query url.Values, body interface{}) (interface{}, error) {
+
+	var reader io.Reader
+
+	if r, ok := body.(io.Reader); ok { // Dumps are streamed as is
+
+		reader = r
+
+	} else if body != nil {
+
+		data, err := json.Marshal(body)
+
+		if err != nil {
+
+			return nil, err
+
+		}
+
+		reader = bytes.NewReader(data)
+
+	}
+
+	resp, err := c.send(ctx, method, path, query, reader)
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	var res interface{}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+
+		return nil, err
+
+	}
+
+	return res, nil
+
+}
+
+// send performs a request and turns error statuses into Go errors, the caller closes the body
+func (c *httpClient) send(ctx context.Context, method string, path string, query url.Values, body io.Reader) (*http.Response, error) {
+
+	target := c.baseURL + path
+
+	if len(query) != 0 {
+
+		target += "?" + query.Encode()
+
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.adminToken != "" && strings.HasPrefix(path, "/admin/") { // Other routes would count it as a rate limit token
+
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+
+	}
+
+	resp, err := c.http.Do(req)
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+
+		return resp, nil
+
+	}
+
+	defer resp.Body.Close()
+
+	var apiErr errs.ErrorResponse
+
+	if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Code != "" {
+
+		return nil, apiError{apiErr}
+
+	}
+
+	return nil, fmt.Errorf("unexpected status %s", resp.Status)
+
+}
+
+// apiError is an error response of the service, details are printed along with the error
+type apiError struct {
+	resp errs.ErrorResponse
+}
+
+func (e apiError) Error() string {
+
+	msg := fmt.Sprintf("%s: %s", e.resp.Error.Code, e.resp.Error.Message)
+
+	for _, detail := range e.resp.Error.Details {
+
+		msg += fmt.Sprintf("\n  %s: %s", detail.Field, detail.Message)
+
+	}
+
+	return msg
+
+}
+
+func (c *httpClient) AddTeam(ctx context.Context, team models.Team) (interface{}, error) {
+
+	return c.do(ctx, http.MethodPost, "/team/add", nil, team)
+
+}
+
+func (c *httpClient) GetTeam(ctx context.Context, teamName string) (interface{}, error) {
+
+	return c.do(ctx, http.MethodGet, "/team/get", url.Values{"team_name": {teamName}}, nil)
+
+}
+
+func (c *httpClient) ListTeams(ctx context.Context, limit int) (interface{}, error) {
+
+	return c.do(ctx, http.MethodGet, "/team/list", url.Values{"limit": {strconv.Itoa(limit)}}, nil)
+
+}
+
+func (c *httpClient) SetIsActive(ctx context.Context, user models.UserActivity) (interface{}, error) {
+
+	return c.do(ctx, http.MethodPost, "/users/setIsActive", nil, user)
+
+}
+
+func (c *httpClient) CreatePR(ctx context.Context, pr models.PullRequestShort) (interface{}, error) {
+
+	return c.do(ctx, http.MethodPost, "/pullRequest/create", nil, pr)
+
+}
+
+func (c *httpClient) MergePR(ctx context.Context, pr models.PRMerge) (interface{}, error) {
+
+	return c.do(ctx, http.MethodPost, "/pullRequest/merge", nil, pr)
+
+}
+
+func (c *httpClient) ReassignPR(ctx context.Context, pr models.PRReassign) (interface{}, error) {
+
+	return c.do(ctx, http.MethodPost, "/pullRequest/reassign", nil, pr)
+
+}
+
+func (c *httpClient) ListPRs(ctx context.Context, status string, limit int) (interface{}, error) {
+
+	query := url.Values{"limit": {strconv.Itoa(limit)}}
+
+	if status != "" {
+
+		query.Set("status", status)
+
+	}
+
+	return c.do(ctx, http.MethodGet, "/pullRequest/list", query, nil)
+
+}
+
+func (c *httpClient) Stats(ctx context.Context) (interface{}, error) {
+
+	return c.do(ctx, http.MethodGet, "/stats", nil, nil)
+
+}
+
+func (c *httpClient) Export(ctx context.Context, format string, w io.Writer) error {
+
+	resp, err := c.send(ctx, http.MethodGet, "/admin/export", url.Values{"format": {format}}, nil)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+
+}
+
+func (c *httpClient) Import(ctx context.Context, r io.Reader, format string, policy string) (interface{}, error) {
+
+	return c.do(ctx, http.MethodPost, "/admin/import", url.Values{"format": {format}, "on_conflict": {policy}}, r)
+
+}
@@ -0,0 +1,494 @@
+// prctl is an admin CLI for the PR reviewer assignment service
+//
+// Usage:
+//
+//	prctl [-mode http|db] [-addr http://localhost:8080] [-timeout 30s] <group> <command> [flags]
+//
+// Commands:
+//
+//	team add -name backend -member u1:Alice -member u2:Bob:inactive | -file team.json
+//	team get -name backend
+//	team list [-limit 100]
+//	user activate|deactivate -id u1
+//	pr create -id pr-1 -name "Add search" -author u1
+//	pr merge -id pr-1
+//	pr reassign -id pr-1 -old u2
+//	pr list [-status OPEN|MERGED] [-limit 100]
+//	stats
+//	export [-format json|ndjson] [-o dump.json]
+//	import -file dump.json [-format json|ndjson] [-on-conflict skip|overwrite|fail]
+//	migrate up|down|status (db mode only)
+//
+// In http mode commands go through a running service, export and import send ADMIN_TOKEN from the
+// environment or .env, in db mode they use POSTGRES_URL and the same service code as the server
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/transfer"
+)
+
+// CLI modes
+const (
+	modeHTTP = "http"
+	modeDB   = "db"
+)
+
+var errUsage = errors.New("usage: prctl [-mode http|db] [-addr url] <team|user|pr|stats|export|import|migrate> <command> [flags]")
+
+func main() {
+
+	mode := flag.String("mode", modeHTTP, "http to call a running service, db to work with Postgres directly")
+
+	addr := flag.String("addr", "http://localhost:8080", "service address for http mode")
+
+	timeout := flag.Duration("timeout", 30*time.Second, "time limit of the whole command")
+
+	configPath := flag.String("config", "", "YAML config file for db mode, defaults to CONFIG_FILE")
+
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	defer cancel()
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+
+	defer cancelTimeout()
+
+	res, err := run(ctx, *mode, *addr, *configPath, flag.Args())
+
+	if err == nil && res != nil { // Failed commands may still return a typed zero value, which is not a result
+
+		printJSON(res)
+
+	}
+
+	if err != nil {
+
+		fmt.Fprintln(os.Stderr, "prctl:", err)
+
+		var failure *errs.ValidationFailure
+
+		if errors.As(err, &failure) { // Direct mode gets details from services, not from an API response
+
+			for _, detail := range failure.Details {
+
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", detail.Field, detail.Message)
+
+			}
+
+		}
+
+		os.Exit(1)
+
+	}
+
+}
+
+// run dispatches a command and returns its result for printing
+func run(ctx context.Context, mode string, addr string, configPath string, args []string) (interface{}, error) {
+
+	if len(args) == 0 {
+
+		return nil, errUsage
+
+	}
+
+	if mode != modeHTTP && mode != modeDB {
+
+		return nil, fmt.Errorf("unknown mode %q", mode)
+
+	}
+
+	_ = godotenv.Load() // .env is optional, the environment may already be set
+
+	var cfg config.Config
+
+	if mode == modeDB {
+
+		var configArgs []string
+
+		if configPath != "" {
+
+			configArgs = []string{"-config", configPath}
+
+		}
+
+		var err error
+
+		cfg, err = config.Load(configArgs)
+
+		if err != nil {
+
+			return nil, err
+
+		}
+
+	}
+
+	if args[0] == "migrate" {
+
+		if mode != modeDB {
+
+			return nil, errors.New("migrate is only available with -mode db")
+
+		}
+
+		if len(args) != 2 {
+
+			return nil, errors.New("usage: prctl -mode db migrate up|down|status")
+
+		}
+
+		return nil, database.Migrate(cfg.Database, args[1])
+
+	}
+
+	var c client
+
+	if mode == modeDB {
+
+		c = newDirectClient(ctx, cfg)
+
+		defer database.DB.Close()
+
+	} else {
+
+		c = newHTTPClient(strings.TrimRight(addr, "/"), os.Getenv("ADMIN_TOKEN"))
+
+	}
+
+	switch args[0] {
+
+	case "team":
+
+		return runTeam(ctx, c, args[1:])
+
+	case "user":
+
+		return runUser(ctx, c, args[1:])
+
+	case "pr":
+
+		return runPR(ctx, c, args[1:])
+
+	case "stats":
+
+		return c.Stats(ctx)
+
+	case "export":
+
+		return nil, runExport(ctx, c, args[1:])
+
+	case "import":
+
+		return runImport(ctx, c, args[1:])
+
+	}
+
+	return nil, errUsage
+
+}
+
+func runTeam(ctx context.Context, c client, args []string) (interface{}, error) {
+
+	if len(args) == 0 {
+
+		return nil, errors.New("usage: prctl team add|get|list [flags]")
+
+	}
+
+	fs := flag.NewFlagSet("team "+args[0], flag.ContinueOnError)
+
+	name := fs.String("name", "", "team name")
+
+	limit := fs.Int("limit", 100, "maximum number of teams")
+
+	file := fs.String("file", "", "JSON file with a team, as accepted by /team/add")
+
+	var members memberFlags
+
+	fs.Var(&members, "member", "team member as user_id:username[:inactive], repeatable")
+
+	if err := fs.Parse(args[1:]); err != nil {
+
+		return nil, err
+
+	}
+
+	switch args[0] {
+
+	case "add":
+
+		team := models.Team{TeamName: *name, Members: members}
+
+		if *file != "" {
+
+			data, err := os.ReadFile(*file)
+
+			if err != nil {
+
+				return nil, err
+
+			}
+
+			if err := json.Unmarshal(data, &team); err != nil {
+
+				return nil, err
+
+			}
+
+		}
+
+		return c.AddTeam(ctx, team)
+
+	case "get":
+
+		return c.GetTeam(ctx, *name)
+
+	case "list":
+
+		return c.ListTeams(ctx, *limit)
+
+	}
+
+	return nil, fmt.Errorf("unknown team command %q", args[0])
+
+}
+
+func runUser(ctx context.Context, c client, args []string) (interface{}, error) {
+
+	if len(args) == 0 {
+
+		return nil, errors.New("usage: prctl user activate|deactivate -id user_id")
+
+	}
+
+	fs := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+
+	id := fs.String("id", "", "user id")
+
+	if err := fs.Parse(args[1:]); err != nil {
+
+		return nil, err
+
+	}
+
+	switch args[0] {
+
+	case "activate":
+
+		return c.SetIsActive(ctx, models.UserActivity{UserID: *id, IsActive: true})
+
+	case "deactivate":
+
+		return c.SetIsActive(ctx, models.UserActivity{UserID: *id, IsActive: false})
+
+	}
+
+	return nil, fmt.Errorf("unknown user command %q", args[0])
+
+}
+
+func runPR(ctx context.Context, c client, args []string) (interface{}, error) {
+
+	if len(args) == 0 {
+
+		return nil, errors.New("usage: prctl pr create|merge|reassign|list [flags]")
+
+	}
+
+	fs := flag.NewFlagSet("pr "+args[0], flag.ContinueOnError)
+
+	id := fs.String("id", "", "pull request id")
+
+	name := fs.String("name", "", "pull request name")
+
+	author := fs.String("author", "", "author user id")
+
+	old := fs.String("old", "", "reviewer to replace")
+
+	status := fs.String("status", "", "filter by status (OPEN, MERGED)")
+
+	limit := fs.Int("limit", 100, "maximum number of pull requests")
+
+	if err := fs.Parse(args[1:]); err != nil {
+
+		return nil, err
+
+	}
+
+	switch args[0] {
+
+	case "create":
+
+		return c.CreatePR(ctx, models.PullRequestShort{PullRequestID: *id, PullRequestName: *name, AuthorID: *author})
+
+	case "merge":
+
+		return c.MergePR(ctx, models.PRMerge{PullRequestID: *id})
+
+	case "reassign":
+
+		return c.ReassignPR(ctx, models.PRReassign{PullRequestID: *id, OldReviewerID: *old})
+
+	case "list":
+
+		return c.ListPRs(ctx, *status, *limit)
+
+	}
+
+	return nil, fmt.Errorf("unknown pr command %q", args[0])
+
+}
+
+// runExport writes a dump to stdout or a file
+func runExport(ctx context.Context, c client, args []string) error {
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+
+	format := fs.String("format", transfer.FormatJSON, "json or ndjson")
+
+	out := fs.String("o", "", "output file, stdout by default")
+
+	if err := fs.Parse(args); err != nil {
+
+		return err
+
+	}
+
+	if _, err := transfer.CheckFormat(*format); err != nil {
+
+		return errors.New("format must be json or ndjson")
+
+	}
+
+	if *out == "" {
+
+		return c.Export(ctx, *format, os.Stdout)
+
+	}
+
+	f, err := os.Create(*out)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	err = c.Export(ctx, *format, f)
+
+	if closeErr := f.Close(); err == nil {
+
+		err = closeErr
+
+	}
+
+	return err
+
+}
+
+// runImport loads a dump file, the format is taken from the .ndjson extension unless set
+func runImport(ctx context.Context, c client, args []string) (interface{}, error) {
+
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+
+	file := fs.String("file", "", "dump file, - for stdin")
+
+	format := fs.String("format", "", "json or ndjson")
+
+	policy := fs.String("on-conflict", "skip", "existing rows: skip, overwrite or fail")
+
+	if err := fs.Parse(args); err != nil {
+
+		return nil, err
+
+	}
+
+	if *file == "" {
+
+		return nil, errors.New("usage: prctl import -file dump.json [-format json|ndjson] [-on-conflict skip|overwrite|fail]")
+
+	}
+
+	if *format == "" && strings.HasSuffix(*file, ".ndjson") {
+
+		*format = transfer.FormatNDJSON
+
+	}
+
+	r := io.Reader(os.Stdin)
+
+	if *file != "-" {
+
+		f, err := os.Open(*file)
+
+		if err != nil {
+
+			return nil, err
+
+		}
+
+		defer f.Close()
+
+		r = f
+
+	}
+
+	return c.Import(ctx, r, *format, *policy)
+
+}
+
+// memberFlags collects repeated -member user_id:username[:inactive] flags
+type memberFlags []models.TeamMember
+
+func (m *memberFlags) String() string {
+
+	return fmt.Sprint(*m)
+
+}
+
+func (m *memberFlags) Set(value string) error {
+
+	parts := strings.Split(value, ":")
+
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "inactive") {
+
+		return fmt.Errorf("member %q must be user_id:username[:inactive]", value)
+
+	}
+
+	*m = append(*m, models.TeamMember{UserID: parts[0], Username: parts[1], IsActive: len(parts) == 2})
+
+	return nil
+
+}
+
+func printJSON(v interface{}) {
+
+	enc := json.NewEncoder(os.Stdout)
+
+	enc.SetIndent("", "  ")
+
+	_ = enc.Encode(v)
+
+}
@@ -26,6 +26,128 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/export": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Выгрузить команды, пользователей и PR с ревьюверами",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат выгрузки: json (по умолчанию) или ndjson",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dump"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена администратора или он неверный",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/import": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Загрузить выгрузку команд, пользователей и PR",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Выгрузка",
+                        "name": "dump",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Dump"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: json (по умолчанию) или ndjson, также определяется по Content-Type application/x-ndjson",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Политика для существующих записей: skip (по умолчанию), overwrite, fail",
+                        "name": "on_conflict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат загрузки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидная выгрузка",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена администратора или он неверный",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись уже существует (on_conflict=fail)",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Тело больше IMPORT_BODY_LIMIT",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Проверяет работоспособность сервиса",
@@ -46,6 +168,52 @@ const docTemplate = `{
                 }
             }
         },
+        "/health/live": {
+            "get": {
+                "description": "Отвечает 200, пока процесс обрабатывает запросы, не проверяет зависимости",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Проверяет базу данных, версию миграций, прогрев кэша и остановку сервиса",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Сервис готов",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
+                        }
+                    },
+                    "503": {
+                        "description": "Одна из зависимостей недоступна или сервис останавливается",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
+                        }
+                    }
+                }
+            }
+        },
         "/pullRequest/create": {
             "post": {
                 "consumes": [
@@ -60,7 +228,7 @@ const docTemplate = `{
                 "summary": "Создать PR и автоматически назначить до 2 ревьюверов из команды автора",
                 "parameters": [
                     {
-                        "description": "Данные пул-реквеста",
+                        "description": "Данные пул-реквеста, priority: low, normal (по умолчанию), high, urgent",
                         "name": "pr",
                         "in": "body",
                         "required": true,
@@ -70,6 +238,15 @@ const docTemplate = `{
                                 "author_id": {
                                     "type": "string"
                                 },
+                                "labels": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "priority": {
+                                    "type": "string"
+                                },
                                 "pull_request_id": {
                                     "type": "string"
                                 },
@@ -92,6 +269,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Автор/команда не найдены",
                         "schema": {
@@ -99,7 +282,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "PR уже существует",
+                        "description": "PR уже существует или правила команды по ревьюверам невыполнимы",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
@@ -107,8 +290,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/merge": {
+        "/pullRequest/createBatch": {
             "post": {
+                "description": "При atomic=true все PR пишутся в одной транзакции: если хотя бы один невалиден, не создаётся ни один (status=aborted). Иначе каждый PR создаётся отдельно. Ответ содержит результат по каждому элементу в порядке запроса",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,37 +302,27 @@ const docTemplate = `{
                 "tags": [
                     "PullRequests"
                 ],
-                "summary": "Пометить PR как MERGED (идемпотентная операция)",
+                "summary": "Создать до 1000 PR за запрос с равномерным назначением ревьюверов внутри пачки",
                 "parameters": [
                     {
-                        "description": "ID пул-реквеста",
-                        "name": "pr",
+                        "description": "Пул-реквесты и режим записи",
+                        "name": "batch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "pull_request_id": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.PRBatchCreate"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "PR в состоянии MERGED",
+                        "description": "Результаты по элементам",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "pr": {
-                                    "$ref": "#/definitions/models.PullRequest"
-                                }
-                            }
+                            "$ref": "#/definitions/models.PRBatchResponse"
                         }
                     },
-                    "404": {
-                        "description": "PR не найден",
+                    "400": {
+                        "description": "Некорректный запрос",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
@@ -156,8 +330,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/reassign": {
+        "/pullRequest/decline": {
             "post": {
+                "description": "В отличие от ` + "`" + `/pullRequest/reassign` + "`" + `, ` + "`" + `replaced_by` + "`" + ` здесь - id нового ревьювера",
                 "consumes": [
                     "application/json"
                 ],
@@ -167,29 +342,21 @@ const docTemplate = `{
                 "tags": [
                     "PullRequests"
                 ],
-                "summary": "Переназначить конкретного ревьювера на другого из его команды",
+                "summary": "Отказаться от ревью с указанием причины; замена ищется как при переназначении, без тех, кто уже отказался от этого PR",
                 "parameters": [
                     {
-                        "description": "Данные для переназначения",
-                        "name": "reassignment",
+                        "description": "PR, ревьювер и причина отказа",
+                        "name": "decline",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "old_user_id": {
-                                    "type": "string"
-                                },
-                                "pull_request_id": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.PRDecline"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Переназначение выполнено",
+                        "description": "Отказ принят, назначен другой ревьювер",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -202,6 +369,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "PR или пользователь не найден",
                         "schema": {
@@ -209,7 +382,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Нарушение доменных правил переназначения",
+                        "description": "PR слит, пользователь не назначен или замены нет",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
@@ -217,43 +390,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/add": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/pullRequest/history": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Создать команду с участниками (создаёт/обновляет пользователей)",
+                "summary": "Получить историю переназначений PR с причинами (ручные и автоматические по SLA)",
                 "parameters": [
                     {
-                        "description": "Данные команды",
-                        "name": "team",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Team"
-                        }
+                        "type": "string",
+                        "description": "Идентификатор PR",
+                        "name": "pull_request_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Команда создана",
+                    "200": {
+                        "description": "История, старые записи первыми",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "team": {
-                                    "$ref": "#/definitions/models.Team"
-                                }
-                            }
+                            "$ref": "#/definitions/models.PRHistory"
                         }
                     },
                     "400": {
-                        "description": "Команда уже существует",
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
@@ -261,33 +430,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/get": {
+        "/pullRequest/list": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Получить команду с участниками",
+                "summary": "Получить последние PR, опционально с фильтром по статусу",
                 "parameters": [
                     {
+                        "enum": [
+                            "OPEN",
+                            "MERGED"
+                        ],
                         "type": "string",
-                        "description": "Уникальное имя команды",
-                        "name": "team_name",
-                        "in": "query",
-                        "required": true
+                        "description": "Статус PR",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество PR (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Объект команды",
+                        "description": "Список PR",
                         "schema": {
-                            "$ref": "#/definitions/models.Team"
+                            "$ref": "#/definitions/models.PullRequestList"
                         }
                     },
-                    "404": {
-                        "description": "Команда не найдена",
+                    "400": {
+                        "description": "Некорректный запрос",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
@@ -295,46 +473,596 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/getReview": {
-            "get": {
+        "/pullRequest/merge": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Получить PR'ы, где пользователь назначен ревьювером",
+                "summary": "Пометить PR как MERGED (идемпотентная операция)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Идентификатор пользователя",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Список PR'ов пользователя",
+                        "description": "ID пул-реквеста",
+                        "name": "pr",
+                        "in": "body",
+                        "required": true,
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "pull_requests": {
-                                    "type": "array",
-                                    "items": {
-                                        "$ref": "#/definitions/models.PullRequestShort"
-                                    }
-                                },
-                                "user_id": {
+                                "pull_request_id": {
                                     "type": "string"
                                 }
                             }
                         }
                     }
-                }
-            }
-        },
-        "/users/setIsActive": {
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PR в состоянии MERGED",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/models.PullRequest"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/mergeBatch": {
+            "post": {
+                "description": "При atomic=true все изменения пишутся в одной транзакции: если хотя бы один PR не найден, не сливается ни один (status=aborted). Уже слитые PR возвращаются как успешные",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Пометить до 1000 PR как MERGED за запрос",
+                "parameters": [
+                    {
+                        "description": "ID пул-реквестов и режим записи",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PRBatchMerge"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результаты по элементам",
+                        "schema": {
+                            "$ref": "#/definitions/models.PRBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reassign": {
+            "post": {
+                "description": "В ` + "`" + `replaced_by` + "`" + ` возвращается id ревьювера, снятого с PR (` + "`" + `old_user_id` + "`" + `); новый ревьювер есть в ` + "`" + `pr.assigned_reviewers` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Переназначить конкретного ревьювера на другого из его команды",
+                "parameters": [
+                    {
+                        "description": "Данные для переназначения",
+                        "name": "reassignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "old_user_id": {
+                                    "type": "string"
+                                },
+                                "pull_request_id": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Переназначение выполнено",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/models.PullRequest"
+                                },
+                                "replaced_by": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR или пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нарушение доменных правил переназначения",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/stale": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Получить назначенные ревью открытых PR, превысившие SLA команды ревьювера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Только ревью этой команды",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество ревью (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Просроченные ревью, самые старые первыми",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaleReviewList"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Получить статистику по командам, пользователям, PR и нагрузке ревьюверов",
+                "responses": {
+                    "200": {
+                        "description": "Статистика",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stats"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/add": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Создать команду с участниками (создаёт/обновляет пользователей)",
+                "parameters": [
+                    {
+                        "description": "Данные команды",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Команда создана",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "team": {
+                                    "$ref": "#/definitions/models.Team"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Команда уже существует или данные невалидны",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Тело запроса больше TEAM_BODY_LIMIT",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/get": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить команду с участниками",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальное имя команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Объект команды",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить последние команды с участниками",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество команд (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список команд",
+                        "schema": {
+                            "$ref": "#/definitions/models.TeamList"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/policy": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить политику ревью команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальное имя команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Политика команды, нули означают значения по умолчанию",
+                        "schema": {
+                            "$ref": "#/definitions/models.TeamPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/setPolicy": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Задать политику ревью команды (SLA ревьювера и автоматическое переназначение)",
+                "parameters": [
+                    {
+                        "description": "Политика команды: 0 в review_sla_hours означает значение по умолчанию, 0 в escalate_after_hours или max_escalations отключает переназначение",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TeamPolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сохраненная политика",
+                        "schema": {
+                            "$ref": "#/definitions/models.TeamPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/getReview": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить очередь ревью пользователя: открытые PR первыми, затем по приоритету (urgent, high, normal, low), затем самые старые",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "OPEN",
+                            "MERGED"
+                        ],
+                        "type": "string",
+                        "description": "Статус PR",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только PR с этой меткой",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список PR'ов пользователя",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pull_requests": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.PullRequestShort"
+                                    }
+                                },
+                                "user_id": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/reviewStream": {
+            "get": {
+                "description": "События передаются между экземплярами через Postgres NOTIFY, поэтому приходят изменения, сделанные любым экземпляром и prctl -mode db. Пока экземпляр не подключён к каналу событий, его подписчики получают только его собственные события, пропущенные события не повторяются",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Поток событий (SSE) о назначении, снятии и merge PR'ов, где пользователь ревьювер",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток событий assigned, unassigned, merged, reminder",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setIsActive": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Установить флаг активности пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные активности пользователя",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "is_active": {
+                                    "type": "boolean"
+                                },
+                                "user_id": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый пользователь",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setProfile": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -345,85 +1073,446 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Установить флаг активности пользователя",
+                "summary": "Установить уровень (junior, middle, senior) и навыки пользователя, используемые правилами подбора ревьюверов",
                 "parameters": [
                     {
-                        "description": "Данные активности пользователя",
+                        "description": "Уровень и навыки, заменяют текущие",
                         "name": "user",
                         "in": "body",
                         "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый пользователь",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "is_active": {
-                                    "type": "boolean"
-                                },
-                                "user_id": {
-                                    "type": "string"
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
                                 }
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "errs.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "TEAM_EXISTS",
+                "PR_EXISTS",
+                "PR_MERGED",
+                "NOT_ASSIGNED",
+                "NO_CANDIDATE",
+                "NOT_FOUND",
+                "VALIDATION_ERROR",
+                "DATABASE_ERROR",
+                "IMPORT_CONFLICT",
+                "UNAVAILABLE",
+                "RATE_LIMITED",
+                "PAYLOAD_TOO_LARGE",
+                "UNAUTHORIZED"
+            ],
+            "x-enum-varnames": [
+                "CodeTeamExists",
+                "CodePRExists",
+                "CodePRMerged",
+                "CodeNotAssigned",
+                "CodeNoCandidate",
+                "CodeNotFound",
+                "CodeValidationError",
+                "CodeDatabaseError",
+                "CodeImportConflict",
+                "CodeUnavailable",
+                "CodeRateLimited",
+                "CodePayloadTooLarge",
+                "CodeUnauthorized"
+            ]
+        },
+        "errs.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "properties": {
+                        "code": {
+                            "$ref": "#/definitions/errs.ErrorCode"
+                        },
+                        "details": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/errs.FieldError"
+                            }
+                        },
+                        "message": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "errs.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "description": "time since assignment, set on reminders",
+                    "type": "integer"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "pull_request": {
+                    "$ref": "#/definitions/models.PullRequestShort"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchItemError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/models.BatchItemError"
+                },
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "ok, failed, aborted (valid, but its atomic batch failed)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Dump": {
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DumpPullRequest"
+                    }
+                },
+                "team_policies": {
+                    "description": "missing in version 1",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeamPolicy"
+                    }
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DumpTeam"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DumpPullRequest": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "history": {
+                    "description": "reviewer changes oldest first, missing in version 1",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRHistoryEntry"
+                    }
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mergedAt": {
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "missing in dumps made before priorities, imported as normal",
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "reviewers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DumpReviewer"
+                    }
+                },
+                "status": {
+                    "description": "OPEN, MERGED",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DumpReviewer": {
+            "type": "object",
+            "properties": {
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assigned_by": {
+                    "type": "string"
+                },
+                "slot": {
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "ASSIGNED, UNASSIGNED",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DumpTeam": {
+            "type": "object",
+            "properties": {
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "up, down",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportCounts": {
+            "type": "object",
+            "properties": {
+                "inserted": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportResult": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "$ref": "#/definitions/models.ImportCounts"
+                },
+                "team_policies": {
+                    "$ref": "#/definitions/models.ImportCounts"
+                },
+                "teams": {
+                    "$ref": "#/definitions/models.ImportCounts"
+                },
+                "users": {
+                    "$ref": "#/definitions/models.ImportCounts"
+                }
+            }
+        },
+        "models.PRBatchCreate": {
+            "type": "object",
+            "required": [
+                "pull_requests"
+            ],
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.PullRequestShort"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Обновлённый пользователь",
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "user": {
-                                    "$ref": "#/definitions/models.User"
-                                }
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Пользователь не найден",
-                        "schema": {
-                            "$ref": "#/definitions/errs.ErrorResponse"
-                        }
+                }
+            }
+        },
+        "models.PRBatchMerge": {
+            "type": "object",
+            "required": [
+                "pull_requests"
+            ],
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.PRMerge"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "errs.ErrorCode": {
-            "type": "string",
-            "enum": [
-                "TEAM_EXISTS",
-                "PR_EXISTS",
-                "PR_MERGED",
-                "NOT_ASSIGNED",
-                "NO_CANDIDATE",
-                "NOT_FOUND",
-                "VALIDATION_ERROR",
-                "DATABASE_ERROR"
+        },
+        "models.PRBatchResponse": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PRDecline": {
+            "type": "object",
+            "required": [
+                "pull_request_id",
+                "reason",
+                "user_id"
             ],
-            "x-enum-varnames": [
-                "CodeTeamExists",
-                "CodePRExists",
-                "CodePRMerged",
-                "CodeNotAssigned",
-                "CodeNoCandidate",
-                "CodeNotFound",
-                "CodeValidationError",
-                "CodeDatabaseError"
-            ]
+            "properties": {
+                "pull_request_id": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
         },
-        "errs.ErrorResponse": {
+        "models.PRHistory": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "object",
-                    "properties": {
-                        "code": {
-                            "$ref": "#/definitions/errs.ErrorCode"
-                        },
-                        "message": {
-                            "type": "string"
-                        }
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRHistoryEntry"
                     }
+                },
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PRHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "reassigned, escalated, declined",
+                    "type": "string"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "replaced_by": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "reviewer taken off the pull request",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PRMerge": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
@@ -442,9 +1531,19 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "string"
                 },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "mergedAt": {
                     "type": "string"
                 },
+                "priority": {
+                    "description": "low, normal, high, urgent",
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "string"
                 },
@@ -457,9 +1556,105 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PullRequestList": {
+            "type": "object",
+            "properties": {
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PullRequest"
+                    }
+                }
+            }
+        },
         "models.PullRequestShort": {
+            "type": "object",
+            "required": [
+                "author_id",
+                "labels",
+                "pull_request_id",
+                "pull_request_name"
+            ],
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "labels": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "description": "normal when empty",
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "normal",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "pull_request_id": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "pull_request_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "status": {
+                    "description": "OPEN, MERGED",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Readiness": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.HealthCheck"
+                    }
+                },
+                "status": {
+                    "description": "up only if every check is up",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewerStats": {
+            "type": "object",
+            "properties": {
+                "decline_rate": {
+                    "description": "declined share of every assignment the user ever got",
+                    "type": "number"
+                },
+                "declined": {
+                    "description": "assignments the user declined",
+                    "type": "integer"
+                },
+                "open_reviews": {
+                    "type": "integer"
+                },
+                "total_reviews": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StaleReview": {
             "type": "object",
             "properties": {
+                "age_seconds": {
+                    "description": "time since the reviewer was assigned",
+                    "type": "integer"
+                },
                 "author_id": {
                     "type": "string"
                 },
@@ -469,37 +1664,163 @@ const docTemplate = `{
                 "pull_request_name": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "OPEN, MERGED",
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "sla_seconds": {
+                    "type": "integer"
+                },
+                "team_name": {
                     "type": "string"
                 }
             }
         },
+        "models.StaleReviewList": {
+            "type": "object",
+            "properties": {
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StaleReview"
+                    }
+                }
+            }
+        },
+        "models.Stats": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer"
+                },
+                "pull_requests": {
+                    "description": "count by status",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reviewers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewerStats"
+                    }
+                },
+                "teams": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.Team": {
             "type": "object",
+            "required": [
+                "members",
+                "team_name"
+            ],
             "properties": {
                 "members": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
                         "$ref": "#/definitions/models.TeamMember"
                     }
                 },
                 "team_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.TeamList": {
+            "type": "object",
+            "properties": {
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Team"
+                    }
                 }
             }
         },
         "models.TeamMember": {
             "type": "object",
+            "required": [
+                "skills",
+                "user_id",
+                "username"
+            ],
             "properties": {
                 "is_active": {
                     "type": "boolean"
                 },
+                "level": {
+                    "description": "middle when empty",
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior"
+                    ]
+                },
+                "skills": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "user_id": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "username": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.TeamPolicy": {
+            "type": "object",
+            "required": [
+                "skill_labels",
+                "team_name"
+            ],
+            "properties": {
+                "escalate_after_hours": {
+                    "description": "Reviews still unanswered after EscalateAfterHours are reassigned automatically,\nat most MaxEscalations times per pull request, zero in either disables escalation",
+                    "type": "integer",
+                    "maximum": 8760,
+                    "minimum": 0
+                },
+                "max_escalations": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "min_senior_reviewers": {
+                    "description": "Reviewer requirements checked on every assignment: at least MinSeniorReviewers senior reviewers,\nand for each pull request label listed in SkillLabels a reviewer with the skill of the same name",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "review_sla_hours": {
+                    "description": "hours a reviewer has before the review is stale",
+                    "type": "integer",
+                    "maximum": 8760,
+                    "minimum": 0
+                },
+                "skill_labels": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
@@ -509,6 +1830,16 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "level": {
+                    "description": "junior, middle, senior",
+                    "type": "string"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "team_name": {
                     "type": "string"
                 },
@@ -519,6 +1850,35 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.UserProfile": {
+            "type": "object",
+            "required": [
+                "level",
+                "skills",
+                "user_id"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior"
+                    ]
+                },
+                "skills": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
         }
     }
 }`
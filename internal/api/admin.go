@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/transfer"
+)
+
+// MIMEApplicationNDJSON is the content type of NDJSON dumps
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// ExportData выгружает все данные сервиса
+
+// @Summary Выгрузить команды, пользователей и PR с ревьюверами
+
+// @Tags Admin
+
+// @Produce json
+
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+
+// @Param format query string false "Формат выгрузки: json (по умолчанию) или ndjson"
+
+// @Success 200 {object} models.Dump "Выгрузка"
+
+// @Failure 400 {object} errs.ErrorResponse "Неизвестный формат"
+
+// @Failure 401 {object} errs.ErrorResponse "Нет токена администратора или он неверный"
+
+// @Router /admin/export [get]
+
+func (h *Handler) ExportData(c echo.Context) error {
+
+	format, err := transfer.CheckFormat(c.QueryParam("format"))
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	contentType := echo.MIMEApplicationJSON
+
+	if format == transfer.FormatNDJSON {
+
+		contentType = MIMEApplicationNDJSON
+
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, contentType) // The status is sent with the first record
+
+	err = transfer.Export(h.requestCtx(c), c.Response(), format)
+
+	if err != nil && !c.Response().Committed {
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return err // A stream cut after the first record can only be dropped, the client sees an incomplete dump
+
+}
+
+// ImportData загружает выгрузку в одной транзакции
+
+// @Summary Загрузить выгрузку команд, пользователей и PR
+
+// @Tags Admin
+
+// @Accept json
+
+// @Produce json
+
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+
+// @Param dump body models.Dump true "Выгрузка"
+
+// @Param format query string false "Формат: json (по умолчанию) или ndjson, также определяется по Content-Type application/x-ndjson"
+
+// @Param on_conflict query string false "Политика для существующих записей: skip (по умолчанию), overwrite, fail"
+
+// @Success 200 {object} models.ImportResult "Результат загрузки"
+
+// @Failure 400 {object} errs.ErrorResponse "Невалидная выгрузка"
+
+// @Failure 401 {object} errs.ErrorResponse "Нет токена администратора или он неверный"
+
+// @Failure 409 {object} errs.ErrorResponse "Запись уже существует (on_conflict=fail)"
+
+// @Failure 413 {object} errs.ErrorResponse "Тело больше IMPORT_BODY_LIMIT"
+
+// @Router /admin/import [post]
+
+func (h *Handler) ImportData(c echo.Context) error {
+
+	format := c.QueryParam("format")
+
+	if format == "" && strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), MIMEApplicationNDJSON) {
+
+		format = transfer.FormatNDJSON
+
+	}
+
+	format, err := transfer.CheckFormat(format)
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	dump, err := transfer.Decode(c.Request().Body, format)
+
+	if errors.Is(err, errs.ErrPayloadTooLarge) {
+
+		return c.JSON(http.StatusRequestEntityTooLarge, errs.PayloadTooLarge())
+
+	}
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	res, err := transfer.Import(h.requestCtx(c), dump, c.QueryParam("on_conflict"))
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrValidation) {
+
+			return c.JSON(http.StatusBadRequest, validationError(err))
+
+		}
+
+		if errors.Is(err, errs.ErrImportConflict) {
+
+			return c.JSON(http.StatusConflict, errs.ImportConflict(err.Error()))
+
+		}
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, res)
+
+}
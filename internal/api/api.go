@@ -3,21 +3,47 @@ package api
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/health"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+)
+
+// Default and maximum page size of list endpoints
+const (
+	defaultListLimit = 100
+
+	maxListLimit = 1000
 )
 
 // Handler manages HTTP request handlers for the PR review service
 type Handler struct {
 	ctx context.Context
+
+	healthTimeout time.Duration // how long readiness waits for the database
+
+	sla config.SLA // default SLA of /pullRequest/stale
 }
 
-func NewHandler(ctx context.Context) *Handler {
+func NewHandler(ctx context.Context, cfg config.Config) *Handler {
+
+	return &Handler{ctx: ctx, healthTimeout: cfg.Server.HealthTimeout, sla: cfg.SLA}
+
+}
+
+// requestCtx joins the request span and logger to the server context, so services are traced
+// and logged as part of the request but are still cancelled only when the server stops
+func (h *Handler) requestCtx(c echo.Context) context.Context {
+
+	reqCtx := c.Request().Context()
+
+	ctx := trace.ContextWithSpan(h.ctx, trace.SpanFromContext(reqCtx))
 
-	return &Handler{ctx: ctx}
+	return logger.FromContext(reqCtx).WithContext(ctx)
 
 }
 
@@ -37,10 +63,56 @@ func NewHandler(ctx context.Context) *Handler {
 
 func (h *Handler) Health(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration) // Start timer for metrics collection
+	return c.JSON(http.StatusOK, http.StatusText(http.StatusOK))
+
+}
+
+// Live проверяет, что процесс жив
+
+// @Summary Liveness probe
+
+// @Description Отвечает 200, пока процесс обрабатывает запросы, не проверяет зависимости
+
+// @Tags health
+
+// @Produce json
+
+// @Success 200 {string} string "OK"
+
+// @Router /health/live [get]
 
-	defer timer.ObserveDuration()
+func (h *Handler) Live(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, http.StatusText(http.StatusOK))
 
 }
+
+// Ready проверяет готовность сервиса принимать трафик
+
+// @Summary Readiness probe
+
+// @Description Проверяет базу данных, версию миграций, прогрев кэша и остановку сервиса
+
+// @Tags health
+
+// @Produce json
+
+// @Success 200 {object} models.Readiness "Сервис готов"
+
+// @Failure 503 {object} models.Readiness "Одна из зависимостей недоступна или сервис останавливается"
+
+// @Router /health/ready [get]
+
+func (h *Handler) Ready(c echo.Context) error {
+
+	res, ok := health.Ready(c.Request().Context(), h.healthTimeout)
+
+	if !ok {
+
+		return c.JSON(http.StatusServiceUnavailable, res)
+
+	}
+
+	return c.JSON(http.StatusOK, res)
+
+}
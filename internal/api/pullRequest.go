@@ -5,12 +5,11 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/sla"
 )
 
 // CreatePullRequest создает новый пул-реквест
@@ -23,33 +22,31 @@ import (
 
 // @Produce json
 
-// @Param pr body object{pull_request_id=string,pull_request_name=string,author_id=string} true "Данные пул-реквеста"
+// @Param pr body object{pull_request_id=string,pull_request_name=string,author_id=string,priority=string,labels=[]string} true "Данные пул-реквеста, priority: low, normal (по умолчанию), high, urgent"
 
 // @Success 201 {object} object{pr=models.PullRequest} "PR создан"
 
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
 // @Failure 404 {object} errs.ErrorResponse "Автор/команда не найдены"
 
-// @Failure 409 {object} errs.ErrorResponse "PR уже существует"
+// @Failure 409 {object} errs.ErrorResponse "PR уже существует или правила команды по ревьюверам невыполнимы"
 
 // @Router /pullRequest/create [post]
 
 func (h *Handler) CreatePullRequest(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
-
-	defer timer.ObserveDuration()
-
 	var bindedPR models.PullRequestShort
 
-	err := c.Bind(&bindedPR)
+	err := bindAndValidate(c, &bindedPR)
 
 	if err != nil {
 
-		return c.JSON(http.StatusBadRequest, errs.ValidationError())
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
 	}
 
-	request, err := pullrequest.Create(h.ctx, bindedPR)
+	request, err := pullrequest.Create(h.requestCtx(c), bindedPR)
 
 	if err != nil {
 
@@ -59,6 +56,12 @@ func (h *Handler) CreatePullRequest(c echo.Context) error {
 
 		}
 
+		if errors.Is(err, errs.ErrNoCandidate) {
+
+			return c.JSON(http.StatusConflict, errs.NoCandidate())
+
+		}
+
 		if errors.Is(err, errs.ErrNotFound) {
 
 			return c.JSON(http.StatusNotFound, errs.NotFound())
@@ -73,6 +76,78 @@ func (h *Handler) CreatePullRequest(c echo.Context) error {
 
 }
 
+// CreatePullRequestBatch создает пачку пул-реквестов
+
+// @Summary Создать до 1000 PR за запрос с равномерным назначением ревьюверов внутри пачки
+
+// @Description При atomic=true все PR пишутся в одной транзакции: если хотя бы один невалиден, не создаётся ни один (status=aborted). Иначе каждый PR создаётся отдельно. Ответ содержит результат по каждому элементу в порядке запроса
+
+// @Tags PullRequests
+
+// @Accept json
+
+// @Produce json
+
+// @Param batch body models.PRBatchCreate true "Пул-реквесты и режим записи"
+
+// @Success 200 {object} models.PRBatchResponse "Результаты по элементам"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Router /pullRequest/createBatch [post]
+
+func (h *Handler) CreatePullRequestBatch(c echo.Context) error {
+
+	var batch models.PRBatchCreate
+
+	err := bindAndValidate(c, &batch)
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	return c.JSON(http.StatusOK, pullrequest.CreateBatch(h.requestCtx(c), batch))
+
+}
+
+// MergePullRequestBatch мержит пачку пул-реквестов
+
+// @Summary Пометить до 1000 PR как MERGED за запрос
+
+// @Description При atomic=true все изменения пишутся в одной транзакции: если хотя бы один PR не найден, не сливается ни один (status=aborted). Уже слитые PR возвращаются как успешные
+
+// @Tags PullRequests
+
+// @Accept json
+
+// @Produce json
+
+// @Param batch body models.PRBatchMerge true "ID пул-реквестов и режим записи"
+
+// @Success 200 {object} models.PRBatchResponse "Результаты по элементам"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Router /pullRequest/mergeBatch [post]
+
+func (h *Handler) MergePullRequestBatch(c echo.Context) error {
+
+	var batch models.PRBatchMerge
+
+	err := bindAndValidate(c, &batch)
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	return c.JSON(http.StatusOK, pullrequest.MergeBatch(h.requestCtx(c), batch))
+
+}
+
 // MergePullRequest мержит пул-реквест
 
 // @Summary Пометить PR как MERGED (идемпотентная операция)
@@ -87,27 +162,25 @@ func (h *Handler) CreatePullRequest(c echo.Context) error {
 
 // @Success 200 {object} object{pr=models.PullRequest} "PR в состоянии MERGED"
 
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
 // @Failure 404 {object} errs.ErrorResponse "PR не найден"
 
 // @Router /pullRequest/merge [post]
 
 func (h *Handler) MergePullRequest(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
+	var bindedPR models.PRMerge
 
-	defer timer.ObserveDuration()
-
-	var bindedPR models.PullRequestShort
-
-	err := c.Bind(&bindedPR)
+	err := bindAndValidate(c, &bindedPR)
 
 	if err != nil {
 
-		return c.JSON(http.StatusBadRequest, errs.ValidationError())
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
 	}
 
-	request, err := pullrequest.Merge(h.ctx, bindedPR)
+	request, err := pullrequest.Merge(h.requestCtx(c), bindedPR)
 
 	if err != nil {
 
@@ -129,6 +202,8 @@ func (h *Handler) MergePullRequest(c echo.Context) error {
 
 // @Summary Переназначить конкретного ревьювера на другого из его команды
 
+// @Description В `replaced_by` возвращается id ревьювера, снятого с PR (`old_user_id`); новый ревьювер есть в `pr.assigned_reviewers`
+
 // @Tags PullRequests
 
 // @Accept json
@@ -139,6 +214,8 @@ func (h *Handler) MergePullRequest(c echo.Context) error {
 
 // @Success 200 {object} object{pr=models.PullRequest,replaced_by=string} "Переназначение выполнено"
 
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
 // @Failure 404 {object} errs.ErrorResponse "PR или пользователь не найден"
 
 // @Failure 409 {object} errs.ErrorResponse "Нарушение доменных правил переназначения"
@@ -147,21 +224,89 @@ func (h *Handler) MergePullRequest(c echo.Context) error {
 
 func (h *Handler) ReassignPullRequest(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
+	var bindedPR models.PRReassign
 
-	defer timer.ObserveDuration()
+	err := bindAndValidate(c, &bindedPR)
 
-	var bindedPR models.PRReassign
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	request, err := pullrequest.Reassign(h.requestCtx(c), bindedPR)
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrPRMerged) {
+
+			return c.JSON(http.StatusConflict, errs.PRMerged())
+
+		}
+
+		if errors.Is(err, errs.ErrNotAssigned) {
+
+			return c.JSON(http.StatusConflict, errs.NotAssigned())
+
+		}
+
+		if errors.Is(err, errs.ErrNoCandidate) {
+
+			return c.JSON(http.StatusConflict, errs.NoCandidate())
+
+		}
+
+		if errors.Is(err, errs.ErrNotFound) {
+
+			return c.JSON(http.StatusNotFound, errs.NotFound())
+
+		}
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, request)
+
+}
+
+// DeclinePullRequest отказывает в ревью пул-реквеста
+
+// @Summary Отказаться от ревью с указанием причины; замена ищется как при переназначении, без тех, кто уже отказался от этого PR
+
+// @Description В отличие от `/pullRequest/reassign`, `replaced_by` здесь - id нового ревьювера
+
+// @Tags PullRequests
 
-	err := c.Bind(&bindedPR)
+// @Accept json
+
+// @Produce json
+
+// @Param decline body models.PRDecline true "PR, ревьювер и причина отказа"
+
+// @Success 200 {object} object{pr=models.PullRequest,replaced_by=string} "Отказ принят, назначен другой ревьювер"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Failure 404 {object} errs.ErrorResponse "PR или пользователь не найден"
+
+// @Failure 409 {object} errs.ErrorResponse "PR слит, пользователь не назначен или замены нет"
+
+// @Router /pullRequest/decline [post]
+
+func (h *Handler) DeclinePullRequest(c echo.Context) error {
+
+	var bindedPR models.PRDecline
+
+	err := bindAndValidate(c, &bindedPR)
 
 	if err != nil {
 
-		return c.JSON(http.StatusBadRequest, errs.ValidationError())
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
 	}
 
-	request, err := pullrequest.Reassign(h.ctx, bindedPR)
+	request, err := pullrequest.Decline(h.requestCtx(c), bindedPR)
 
 	if err != nil {
 
@@ -196,3 +341,137 @@ func (h *Handler) ReassignPullRequest(c echo.Context) error {
 	return c.JSON(http.StatusOK, request)
 
 }
+
+// ListPullRequests получает список пул-реквестов
+
+// @Summary Получить последние PR, опционально с фильтром по статусу
+
+// @Tags PullRequests
+
+// @Produce json
+
+// @Param status query string false "Статус PR" Enums(OPEN, MERGED)
+
+// @Param limit query int false "Максимальное количество PR (1-1000, по умолчанию 100)"
+
+// @Success 200 {object} models.PullRequestList "Список PR"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Router /pullRequest/list [get]
+
+func (h *Handler) ListPullRequests(c echo.Context) error {
+
+	status := c.QueryParam("status")
+
+	if status != "" && status != pullrequest.OpenStatus && status != pullrequest.MergeStatus {
+
+		return c.JSON(http.StatusBadRequest, errs.ValidationError(errs.FieldError{Field: "status", Message: "must be OPEN or MERGED"}))
+
+	}
+
+	limit, err := limitQuery(c, defaultListLimit, maxListLimit)
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	prs, err := pullrequest.List(h.requestCtx(c), status, limit)
+
+	if err != nil {
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, prs)
+
+}
+
+// ListStaleReviews получает просроченные ревью
+
+// @Summary Получить назначенные ревью открытых PR, превысившие SLA команды ревьювера
+
+// @Tags PullRequests
+
+// @Produce json
+
+// @Param team_name query string false "Только ревью этой команды"
+
+// @Param limit query int false "Максимальное количество ревью (1-1000, по умолчанию 100)"
+
+// @Success 200 {object} models.StaleReviewList "Просроченные ревью, самые старые первыми"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Router /pullRequest/stale [get]
+
+func (h *Handler) ListStaleReviews(c echo.Context) error {
+
+	limit, err := limitQuery(c, defaultListLimit, maxListLimit)
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	reviews, err := sla.Stale(h.requestCtx(c), h.sla, c.QueryParam("team_name"), limit)
+
+	if err != nil {
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, reviews)
+
+}
+
+// GetPullRequestHistory получает историю смены ревьюверов PR
+
+// @Summary Получить историю переназначений PR с причинами (ручные и автоматические по SLA)
+
+// @Tags PullRequests
+
+// @Produce json
+
+// @Param pull_request_id query string true "Идентификатор PR"
+
+// @Success 200 {object} models.PRHistory "История, старые записи первыми"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Failure 404 {object} errs.ErrorResponse "PR не найден"
+
+// @Router /pullRequest/history [get]
+
+func (h *Handler) GetPullRequestHistory(c echo.Context) error {
+
+	pull_request_id, err := requireQuery(c, "pull_request_id")
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	history, err := pullrequest.History(h.requestCtx(c), pull_request_id)
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrNotFound) {
+
+			return c.JSON(http.StatusNotFound, errs.NotFound())
+
+		}
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, history)
+
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/stats"
+)
+
+// GetStats получает статистику сервиса
+
+// @Summary Получить статистику по командам, пользователям, PR и нагрузке ревьюверов
+
+// @Tags Stats
+
+// @Produce json
+
+// @Success 200 {object} models.Stats "Статистика"
+
+// @Router /stats [get]
+
+func (h *Handler) GetStats(c echo.Context) error {
+
+	res, err := stats.Get(h.requestCtx(c))
+
+	if err != nil {
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, res)
+
+}
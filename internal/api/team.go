@@ -5,10 +5,8 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/team"
 )
@@ -27,29 +25,35 @@ import (
 
 // @Success 201 {object} object{team=models.Team} "Команда создана"
 
-// @Failure 400 {object} errs.ErrorResponse "Команда уже существует"
+// @Failure 400 {object} errs.ErrorResponse "Команда уже существует или данные невалидны"
 
-// @Router /team/add [post]
+// @Failure 413 {object} errs.ErrorResponse "Тело запроса больше TEAM_BODY_LIMIT"
 
-func (h *Handler) AddTeam(c echo.Context) error {
+// @Failure 429 {object} errs.ErrorResponse "Превышен лимит запросов"
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
+// @Router /team/add [post]
 
-	defer timer.ObserveDuration()
+func (h *Handler) AddTeam(c echo.Context) error {
 
 	var bindedTeam models.Team
 
 	var TeamResponse models.TeamResponse
 
-	err := c.Bind(&bindedTeam)
+	err := bindAndValidate(c, &bindedTeam)
+
+	if errors.Is(err, errs.ErrPayloadTooLarge) {
+
+		return c.JSON(http.StatusRequestEntityTooLarge, errs.PayloadTooLarge())
+
+	}
 
 	if err != nil {
 
-		return c.JSON(http.StatusBadRequest, errs.ValidationError())
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
 	}
 
-	TeamResponse, err = team.Add(bindedTeam, h.ctx)
+	TeamResponse, err = team.Add(bindedTeam, h.requestCtx(c))
 
 	if err != nil {
 
@@ -59,6 +63,12 @@ func (h *Handler) AddTeam(c echo.Context) error {
 
 		}
 
+		if errors.Is(err, errs.ErrValidation) {
+
+			return c.JSON(http.StatusBadRequest, validationError(err))
+
+		}
+
 		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
 
 	}
@@ -79,19 +89,23 @@ func (h *Handler) AddTeam(c echo.Context) error {
 
 // @Success 200 {object} models.Team "Объект команды"
 
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
 // @Failure 404 {object} errs.ErrorResponse "Команда не найдена"
 
 // @Router /team/get [get]
 
 func (h *Handler) GetTeam(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
+	team_name, err := requireQuery(c, "team_name")
 
-	defer timer.ObserveDuration()
+	if err != nil {
 
-	team_name := c.QueryParam("team_name")
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
-	team, err := team.Get(team_name, h.ctx)
+	}
+
+	team, err := team.Get(team_name, h.requestCtx(c))
 
 	if err != nil {
 
@@ -108,3 +122,135 @@ func (h *Handler) GetTeam(c echo.Context) error {
 	return c.JSON(http.StatusOK, team)
 
 }
+
+// ListTeams получает список команд с участниками
+
+// @Summary Получить последние команды с участниками
+
+// @Tags Teams
+
+// @Produce json
+
+// @Param limit query int false "Максимальное количество команд (1-1000, по умолчанию 100)"
+
+// @Success 200 {object} models.TeamList "Список команд"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Router /team/list [get]
+
+func (h *Handler) ListTeams(c echo.Context) error {
+
+	limit, err := limitQuery(c, defaultListLimit, maxListLimit)
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	teams, err := team.List(h.requestCtx(c), limit)
+
+	if err != nil {
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, teams)
+
+}
+
+// SetTeamPolicy задает политику ревью команды
+
+// @Summary Задать политику ревью команды (SLA ревьювера и автоматическое переназначение)
+
+// @Tags Teams
+
+// @Accept json
+
+// @Produce json
+
+// @Param policy body models.TeamPolicy true "Политика команды: 0 в review_sla_hours означает значение по умолчанию, 0 в escalate_after_hours или max_escalations отключает переназначение"
+
+// @Success 200 {object} models.TeamPolicy "Сохраненная политика"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Failure 404 {object} errs.ErrorResponse "Команда не найдена"
+
+// @Router /team/setPolicy [post]
+
+func (h *Handler) SetTeamPolicy(c echo.Context) error {
+
+	var bindedPolicy models.TeamPolicy
+
+	if err := bindAndValidate(c, &bindedPolicy); err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	policy, err := team.SetPolicy(bindedPolicy, h.requestCtx(c))
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrNotFound) {
+
+			return c.JSON(http.StatusNotFound, errs.NotFound())
+
+		}
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, policy)
+
+}
+
+// GetTeamPolicy получает политику ревью команды
+
+// @Summary Получить политику ревью команды
+
+// @Tags Teams
+
+// @Produce json
+
+// @Param team_name query string true "Уникальное имя команды"
+
+// @Success 200 {object} models.TeamPolicy "Политика команды, нули означают значения по умолчанию"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Failure 404 {object} errs.ErrorResponse "Команда не найдена"
+
+// @Router /team/policy [get]
+
+func (h *Handler) GetTeamPolicy(c echo.Context) error {
+
+	team_name, err := requireQuery(c, "team_name")
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	policy, err := team.GetPolicy(team_name, h.requestCtx(c))
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrNotFound) {
+
+			return c.JSON(http.StatusNotFound, errs.NotFound())
+
+		}
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, policy)
+
+}
@@ -1,19 +1,24 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/team"
 )
 
+// sseHeartbeat is the interval of keep-alive comments in review streams
+const sseHeartbeat = 15 * time.Second
+
 // SetUserIsActive обновляет статус активности пользователя
 
 // @Summary Установить флаг активности пользователя
@@ -28,27 +33,75 @@ import (
 
 // @Success 200 {object} object{user=models.User} "Обновлённый пользователь"
 
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
 // @Failure 404 {object} errs.ErrorResponse "Пользователь не найден"
 
 // @Router /users/setIsActive [post]
 
 func (h *Handler) SetUserIsActive(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
+	var bindedUser models.UserActivity
 
-	defer timer.ObserveDuration()
+	err := bindAndValidate(c, &bindedUser)
 
-	var bindedUser models.UserActivity
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	user, err := team.SetActive(bindedUser, h.requestCtx(c))
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrNotFound) {
+
+			return c.JSON(http.StatusNotFound, errs.NotFound())
+
+		}
+
+		return c.JSON(http.StatusInternalServerError, errs.DatabaseError())
+
+	}
+
+	return c.JSON(http.StatusOK, user)
+
+}
+
+// SetUserProfile обновляет уровень и навыки пользователя
+
+// @Summary Установить уровень (junior, middle, senior) и навыки пользователя, используемые правилами подбора ревьюверов
 
-	err := c.Bind(&bindedUser)
+// @Tags Users
+
+// @Accept json
+
+// @Produce json
+
+// @Param user body models.UserProfile true "Уровень и навыки, заменяют текущие"
+
+// @Success 200 {object} object{user=models.User} "Обновлённый пользователь"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Failure 404 {object} errs.ErrorResponse "Пользователь не найден"
+
+// @Router /users/setProfile [post]
+
+func (h *Handler) SetUserProfile(c echo.Context) error {
+
+	var bindedUser models.UserProfile
+
+	err := bindAndValidate(c, &bindedUser)
 
 	if err != nil {
 
-		return c.JSON(http.StatusBadRequest, errs.ValidationError())
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
 	}
 
-	user, err := team.SetActive(bindedUser, h.ctx)
+	user, err := team.SetProfile(bindedUser, h.requestCtx(c))
 
 	if err != nil {
 
@@ -68,7 +121,7 @@ func (h *Handler) SetUserIsActive(c echo.Context) error {
 
 // GetUserReview получает пул-реквесты для ревью пользователя
 
-// @Summary Получить PR'ы, где пользователь назначен ревьювером
+// @Summary Получить очередь ревью пользователя: открытые PR первыми, затем по приоритету (urgent, high, normal, low), затем самые старые
 
 // @Tags Users
 
@@ -76,20 +129,130 @@ func (h *Handler) SetUserIsActive(c echo.Context) error {
 
 // @Param user_id query string true "Идентификатор пользователя"
 
+// @Param status query string false "Статус PR" Enums(OPEN, MERGED)
+
+// @Param label query string false "Только PR с этой меткой"
+
 // @Success 200 {object} object{user_id=string,pull_requests=[]models.PullRequestShort} "Список PR'ов пользователя"
 
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
 // @Router /users/getReview [get]
 
 func (h *Handler) GetUserReview(c echo.Context) error {
 
-	timer := prometheus.NewTimer(metrics.HttpDuration)
+	user_id, err := requireQuery(c, "user_id")
 
-	defer timer.ObserveDuration()
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
 
-	user_id := c.QueryParam("user_id")
+	}
 
-	requests := pullrequest.GetPR(h.ctx, user_id)
+	filter := models.ReviewFilter{Status: c.QueryParam("status"), Label: c.QueryParam("label")}
+
+	if filter.Status != "" && filter.Status != pullrequest.OpenStatus && filter.Status != pullrequest.MergeStatus {
+
+		return c.JSON(http.StatusBadRequest, errs.ValidationError(errs.FieldError{Field: "status", Message: "must be OPEN or MERGED"}))
+
+	}
+
+	requests := pullrequest.GetPR(h.requestCtx(c), user_id, filter)
 
 	return c.JSON(http.StatusOK, requests)
 
 }
+
+// GetUserReviewStream отдаёт поток событий назначения ревью пользователя
+
+// @Summary Поток событий (SSE) о назначении, снятии и merge PR'ов, где пользователь ревьювер
+
+// @Description События передаются между экземплярами через Postgres NOTIFY, поэтому приходят изменения, сделанные любым экземпляром и prctl -mode db. Пока экземпляр не подключён к каналу событий, его подписчики получают только его собственные события, пропущенные события не повторяются
+
+// @Tags Users
+
+// @Produce text/event-stream
+
+// @Param user_id query string true "Идентификатор пользователя"
+
+// @Success 200 {object} events.Event "Поток событий assigned, unassigned, merged, reminder"
+
+// @Failure 400 {object} errs.ErrorResponse "Некорректный запрос"
+
+// @Router /users/reviewStream [get]
+
+func (h *Handler) GetUserReviewStream(c echo.Context) error {
+
+	user_id, err := requireQuery(c, "user_id")
+
+	if err != nil {
+
+		return c.JSON(http.StatusBadRequest, validationError(err))
+
+	}
+
+	stream, unsubscribe := events.Default.Subscribe(user_id)
+
+	defer unsubscribe()
+
+	res := c.Response()
+
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+
+	res.WriteHeader(http.StatusOK)
+
+	res.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat) // Keep proxies from closing an idle connection
+
+	defer heartbeat.Stop()
+
+	for {
+
+		select {
+
+		case <-c.Request().Context().Done(): // Client went away
+
+			return nil
+
+		case <-h.ctx.Done(): // Server is shutting down
+
+			return nil
+
+		case <-heartbeat.C:
+
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+
+				return nil
+
+			}
+
+			res.Flush()
+
+		case event := <-stream:
+
+			data, err := json.Marshal(event)
+
+			if err != nil {
+
+				return err
+
+			}
+
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+
+				return nil
+
+			}
+
+			res.Flush()
+
+		}
+
+	}
+
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+func TestGetUserReviewStream(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	e := echo.New()
+
+	e.GET("/users/reviewStream", NewHandler(ctx, config.Default()).GetUserReviewStream)
+
+	srv := httptest.NewServer(e)
+
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/users/reviewStream")
+
+	require.NoError(t, err)
+
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/users/reviewStream?user_id=sse_user")
+
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get(echo.HeaderContentType))
+
+	// Headers are flushed after subscribing, so the event cannot be missed
+	events.Default.PublishReview(events.ReviewAssigned, models.PullRequest{PullRequestID: "pr_sse", Status: "OPEN"}, "sse_user")
+
+	lines := make(chan string)
+
+	go func() {
+
+		scanner := bufio.NewScanner(resp.Body)
+
+		for scanner.Scan() {
+
+			lines <- scanner.Text()
+
+		}
+
+		close(lines)
+
+	}()
+
+	select {
+
+	case line := <-lines:
+
+		assert.Equal(t, "event: assigned", line)
+
+	case <-time.After(2 * time.Second):
+
+		t.Fatal("no event received")
+
+	}
+
+	line := <-lines
+
+	assert.True(t, strings.HasPrefix(line, "data: "))
+
+	assert.Contains(t, line, `"pull_request_id":"pr_sse"`)
+
+	cancel() // Server shutdown ends the stream
+
+	for range lines {
+
+	}
+
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+)
+
+// bindAndValidate binds the request body into dst and validates it
+func bindAndValidate(c echo.Context, dst interface{}) error {
+
+	if err := c.Bind(dst); err != nil {
+
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) { // Body cut by the route body limit
+
+			return errs.ErrPayloadTooLarge
+
+		}
+
+		return &errs.ValidationFailure{Details: []errs.FieldError{{Field: "body", Message: "must be a valid JSON object"}}}
+
+	}
+
+	return c.Validate(dst)
+
+}
+
+// requireQuery returns a non-empty query parameter or a validation failure
+func requireQuery(c echo.Context, name string) (string, error) {
+
+	value := c.QueryParam(name)
+
+	if strings.TrimSpace(value) == "" {
+
+		return "", &errs.ValidationFailure{Details: []errs.FieldError{{Field: name, Message: "is required"}}}
+
+	}
+
+	return value, nil
+
+}
+
+// limitQuery parses an optional positive limit query parameter capped at max
+func limitQuery(c echo.Context, def int, max int) (int, error) {
+
+	value := c.QueryParam("limit")
+
+	if value == "" {
+
+		return def, nil
+
+	}
+
+	limit, err := strconv.Atoi(value)
+
+	if err != nil || limit < 1 || limit > max {
+
+		return 0, &errs.ValidationFailure{Details: []errs.FieldError{{Field: "limit", Message: fmt.Sprintf("must be a number from 1 to %d", max)}}}
+
+	}
+
+	return limit, nil
+
+}
+
+// validationError builds a VALIDATION_ERROR response with details when err carries them
+func validationError(err error) errs.ErrorResponse {
+
+	var failure *errs.ValidationFailure
+
+	if errors.As(err, &failure) {
+
+		return errs.ValidationError(failure.Details...)
+
+	}
+
+	return errs.ValidationError()
+
+}
@@ -1,23 +1,53 @@
 package app
 
 import (
+	"errors"
+	"flag"
+	"os"
+
 	"github.com/joho/godotenv"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/ratelimit"
 )
 
-func Init() {
+// Init loads the configuration from the YAML file, env and command line args and sets up the logger and the reloadable components
+func Init(args []string) config.Config {
+
+	if err := godotenv.Load(); err != nil { // .env is optional, the environment may already be set
+
+		logger.Info("no .env file, using the environment")
+
+	}
+
+	cfg, err := config.Load(args)
+
+	if errors.Is(err, flag.ErrHelp) {
 
-	if err := godotenv.Load(); err != nil { // Load environment variables from .env file
+		config.Usage(os.Stderr)
 
-		logger.Fatal(err, "No .env file found")
+		os.Exit(0)
 
 	}
 
+	if err != nil {
+
+		logger.Fatal(err, "invalid configuration") // The message lists every problem
+
+	}
+
+	logger.Init(cfg.Log.Format, cfg.Log.Level)
+
 	metrics.Init() // Initialize Prometheus metrics collectors
 
-	config.VarsInit() // Load and validate configuration from environment variables
+	// Reloadable settings live in the packages that use them so a reload can swap them, the rest is passed explicitly
+	pullrequest.Init(cfg.Assignment)
+
+	ratelimit.Init(cfg.RateLimit)
+
+	return cfg
 
 }
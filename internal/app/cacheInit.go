@@ -2,137 +2,110 @@ package app
 
 import (
 	"context"
-	"log"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
 )
 
-// LoadCacheFromDB preloads data from database into in-memory cache
-func LoadCacheFromDB(ctx context.Context) error {
+// InitCaches creates empty caches and wraps the database with them
+func InitCaches(cfg config.Cache) {
 
-	cache.InitCache() // Initialize cache instances
+	cache.InitCache(cfg) // Initialize cache instances
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	repository.Init() // Wrap database with the new caches
 
-	defer cancel()
+}
 
-	rows, err := database.DB.Query(dbCtx, `SELECT team_name FROM teams`) // Load all teams from database into cache
+// LoadCacheFromDB preloads data from database into in-memory cache according to the warm-up policy
+// Call it after InitCaches once the invalidation listener is subscribed, otherwise writes made meanwhile are missed
+func LoadCacheFromDB(ctx context.Context, cfg config.Cache) error {
 
-	if err != nil {
+	cachingStarted.Store(true) // A listener subscribing from now on may have missed writes to cached entries
 
-		logger.Info("failed to load teams for cache: %v", err)
+	if cfg.Warmup == config.WarmupNone {
 
-		return err
+		logger.Info("cache warm-up disabled")
 
-	}
+		return nil
 
-	defer rows.Close()
+	}
 
-	for rows.Next() {
+	limit := cfg.Cap // Entries beyond capacity would be evicted right away
 
-		var teamName string
+	if cfg.Warmup == config.WarmupRecent && cfg.WarmupLimit > 0 && cfg.WarmupLimit < limit {
 
-		if err := rows.Scan(&teamName); err != nil {
+		limit = cfg.WarmupLimit
 
-			logger.Info("failed to scan team name: %v", err)
+	}
 
-			continue
+	status := ""
 
-		}
+	if cfg.Warmup == config.WarmupOpen {
 
-		team, err, _ := database.GetTeamFromDB(dbCtx, teamName)
+		status = pullrequest.OpenStatus
 
-		if err != nil {
+	}
 
-			logger.Info("failed to load team %s: %v", teamName, err)
+	teams, users, prs := 0, 0, 0
 
-			continue
+	// Load teams with members into cache
+	err := database.LoadTeamsFromDB(ctx, limit, func(team models.Team) {
 
-		}
+		cache.TeamCache.Set(team.TeamName, team)
 
-		cache.TeamCache.Set(teamName, team)
+		teams++
 
-	}
-
-	userRows, err := database.DB.Query(dbCtx, `SELECT user_id FROM users`) // Load all users from database into cache
+	})
 
 	if err != nil {
 
-		log.Printf("failed to load users for cache: %v", err)
+		logger.Error(err, "failed to load teams for cache")
 
 		return err
 
 	}
 
-	defer userRows.Close()
-
-	for userRows.Next() {
-
-		var userID string
-
-		if err := userRows.Scan(&userID); err != nil {
-
-			log.Printf("failed to scan user id: %v", err)
-
-			continue
-
-		}
+	// Load users into cache
+	err = database.LoadUsersFromDB(ctx, limit, func(user models.User) {
 
-		user, err, _ := database.GetUserFromDB(dbCtx, userID)
+		cache.UserCache.Set(user.UserID, user)
 
-		if err != nil {
+		users++
 
-			log.Printf("failed to load user %s: %v", userID, err)
-
-			continue
-
-		}
-
-		cache.UserCache.Set(userID, user)
-
-	}
-
-	prRows, err := database.DB.Query(dbCtx, `SELECT pull_request_id FROM pull_requests`) // Load all pr from database into cache
+	})
 
 	if err != nil {
 
-		log.Printf("failed to load PRs for cache: %v", err)
+		logger.Error(err, "failed to load users for cache")
 
 		return err
 
 	}
 
-	defer prRows.Close()
-
-	for prRows.Next() {
-
-		var prID string
+	// Load pull requests into cache
+	err = database.LoadPRsFromDB(ctx, limit, status, func(pr models.PullRequest) {
 
-		if err := prRows.Scan(&prID); err != nil {
+		cache.PRcache.Set(pr.PullRequestID, pr)
 
-			log.Printf("failed to scan PR id: %v", err)
+		prs++
 
-			continue
+	})
 
-		}
-
-		pr, err, _ := database.GetPRFromDB(dbCtx, prID)
-
-		if err != nil {
-
-			log.Printf("failed to load PR %s: %v", prID, err)
-
-			continue
+	if err != nil {
 
-		}
+		logger.Error(err, "failed to load PRs for cache")
 
-		cache.PRcache.Set(prID, pr)
+		return err
 
 	}
 
+	logger.Info("cache warmed up", "policy", cfg.Warmup, "teams", teams, "users", users, "prs", prs)
+
 	return nil
 
 }
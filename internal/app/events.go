@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+)
+
+// StartEventRelay shares review events between instances through Postgres, so /users/reviewStream
+// gets the events of writes made by any instance or by prctl in db mode
+// While the listener is disconnected events only reach subscribers of the instance that produced them
+func StartEventRelay(ctx context.Context) {
+
+	go database.ListenEvents(ctx, events.Default.Deliver, func() {
+
+		events.Default.SetRelay(database.PublishEvent)
+
+	}, func() {
+
+		events.Default.SetRelay(nil)
+
+	})
+
+}
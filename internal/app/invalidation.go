@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+)
+
+// cachingStarted is set once caches may hold entries, before that a new subscription has nothing to purge
+var cachingStarted atomic.Bool
+
+// StartCacheInvalidation listens for writes made by other instances and evicts the affected cache keys
+// The returned channel is closed once the listener is subscribed, warm-up should wait for it
+func StartCacheInvalidation(ctx context.Context) <-chan struct{} {
+
+	subscribed := make(chan struct{})
+
+	var once sync.Once
+
+	go database.ListenInvalidations(ctx, evictInvalidated, func() {
+
+		onSubscribed()
+
+		once.Do(func() { close(subscribed) })
+
+	})
+
+	return subscribed
+
+}
+
+// onSubscribed runs every time LISTEN succeeds, notifications sent before it are lost,
+// so nothing cached before it can be trusted
+func onSubscribed() {
+
+	if !cachingStarted.Load() { // Subscribed before warm-up, the caches are still empty
+
+		return
+
+	}
+
+	logger.Info("cache invalidation listener subscribed, purging caches")
+
+	purgeCaches()
+
+}
+
+// evictInvalidated drops a single entry named by an invalidation message
+func evictInvalidated(msg database.Invalidation) {
+
+	switch msg.Kind {
+
+	case database.InvalidateTeam:
+
+		cache.TeamCache.Delete(msg.Key)
+
+	case database.InvalidateUser:
+
+		cache.UserCache.Delete(msg.Key)
+
+	case database.InvalidatePR:
+
+		cache.PRcache.Delete(msg.Key)
+
+	case database.InvalidateAll:
+
+		purgeCaches()
+
+	default:
+
+		logger.Debug("unknown cache invalidation kind", "kind", msg.Kind)
+
+	}
+
+}
+
+func purgeCaches() {
+
+	cache.TeamCache.Purge()
+
+	cache.UserCache.Purge()
+
+	cache.PRcache.Purge()
+
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+func TestInvalidation_EveryWriteEvictsKey(t *testing.T) {
+
+	cache.InitCache(config.Default().Cache)
+
+	cache.TeamCache.Set("backend", models.Team{TeamName: "backend"})
+
+	cache.PRcache.Set("pr1", models.PullRequest{PullRequestID: "pr1"})
+
+	deliver := func(origin string, kind string, key string) {
+
+		payload, err := json.Marshal(database.Invalidation{Origin: origin, Kind: kind, Key: key})
+
+		require.NoError(t, err)
+
+		if msg, ok := database.DecodeInvalidation(string(payload)); ok {
+
+			evictInvalidated(msg)
+
+		}
+
+	}
+
+	deliver("other-instance", database.InvalidateTeam, "backend")
+
+	_, ok := cache.TeamCache.Get("backend")
+
+	assert.False(t, ok)
+
+	_, ok = cache.PRcache.Get("pr1")
+
+	assert.True(t, ok) // Only the named key is evicted
+
+	// A read racing an own write may cache the old value after the write evicted it
+	deliver(database.InstanceID, database.InvalidatePR, "pr1")
+
+	_, ok = cache.PRcache.Get("pr1")
+
+	assert.False(t, ok)
+
+}
+
+func TestInvalidation_SubscribeAfterCachingStartedPurges(t *testing.T) {
+
+	cache.InitCache(config.Default().Cache)
+
+	cachingStarted.Store(false)
+
+	t.Cleanup(func() { cachingStarted.Store(false) })
+
+	cache.TeamCache.Set("backend", models.Team{TeamName: "backend"})
+
+	onSubscribed() // Subscribed before warm-up, nothing could have been missed
+
+	_, ok := cache.TeamCache.Get("backend")
+
+	assert.True(t, ok)
+
+	cfg := config.Default().Cache
+
+	cfg.Warmup = config.WarmupNone
+
+	require.NoError(t, LoadCacheFromDB(context.Background(), cfg))
+
+	onSubscribed() // Subscribed late or reconnected, writes made meanwhile were not delivered
+
+	_, ok = cache.TeamCache.Get("backend")
+
+	assert.False(t, ok)
+
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/ratelimit"
+)
+
+// configPollInterval is how often the config file is checked for changes
+const configPollInterval = 2 * time.Second
+
+// Reloader loads the configuration again and applies the settings that can change without a restart
+type Reloader struct {
+	args []string // command line the service was started with
+
+	current config.Config // running configuration
+
+	mu sync.Mutex
+}
+
+func NewReloader(args []string, cfg config.Config) *Reloader {
+
+	return &Reloader{args: args, current: cfg}
+
+}
+
+// Reload re-reads file, env and flags, an invalid configuration is logged and the running one is kept
+func (r *Reloader) Reload() error {
+
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	next, err := config.Load(r.args)
+
+	if err != nil {
+
+		logger.Error(err, "config reload rejected")
+
+		return err
+
+	}
+
+	if sections := r.current.RestartRequired(next); len(sections) != 0 {
+
+		logger.Info("config changes ignored until restart", "sections", sections)
+
+	}
+
+	r.current.Log.Level = next.Log.Level
+
+	r.current.Cache.TTL = next.Cache.TTL
+
+	r.current.Assignment = next.Assignment
+
+	r.current.RateLimit = next.RateLimit
+
+	applyReloadable(r.current)
+
+	logger.Info("config reloaded", "log_level", r.current.Log.Level, "cache_ttl", r.current.Cache.TTL.String(),
+
+		"reviewers", r.current.Assignment.Reviewers, "strategy", r.current.Assignment.Strategy,
+
+		"read_rate", r.current.RateLimit.ReadRate, "write_rate", r.current.RateLimit.WriteRate)
+
+	return nil
+
+}
+
+// Watch reloads the configuration when the config file changes, until ctx is done
+func (r *Reloader) Watch(ctx context.Context) {
+
+	path := r.current.File
+
+	if path == "" { // Only env and flags, reload happens on SIGHUP
+
+		return
+
+	}
+
+	last, _ := os.Stat(path)
+
+	ticker := time.NewTicker(configPollInterval)
+
+	defer ticker.Stop()
+
+	for {
+
+		select {
+
+		case <-ctx.Done():
+
+			return
+
+		case <-ticker.C:
+
+		}
+
+		info, err := os.Stat(path)
+
+		if err != nil { // Editors replace files, the next tick sees the new one
+
+			continue
+
+		}
+
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+
+			continue
+
+		}
+
+		last = info
+
+		_ = r.Reload() // Errors are logged by Reload
+
+	}
+
+}
+
+// applyReloadable pushes the reloadable settings to running components, each one is swapped as a whole
+func applyReloadable(cfg config.Config) {
+
+	if err := logger.SetLevel(cfg.Log.Level); err != nil {
+
+		logger.Error(err, "failed to set log level")
+
+	}
+
+	cache.SetTTL(cfg.Cache.TTL)
+
+	pullrequest.Init(cfg.Assignment)
+
+	ratelimit.Init(cfg.RateLimit)
+
+}
@@ -2,47 +2,110 @@ package app
 
 import (
 	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/api"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/health"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/ratelimit"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/validation"
 )
 
 // StartServer initializes and configures the HTTP server
-func StartServer(ctx context.Context) *echo.Echo {
+func StartServer(ctx context.Context, cfg config.Config) *echo.Echo {
 
-	handler := api.NewHandler(ctx) // Create API handler with context
+	handler := api.NewHandler(ctx, cfg) // Create API handler with context and its settings
 
 	e := echo.New() // Initialize Echo framework
 
+	e.Validator = validation.New() // Validate request bodies by struct tags
+
+	e.IPExtractor = ratelimit.ExtractIP // X-Forwarded-For only from RATE_LIMIT_TRUSTED_PROXIES
+
+	// Trace every API request, probes and scrapes would only add noise
+	e.Use(otelecho.Middleware(tracing.ServiceName, otelecho.WithSkipper(systemRequest)))
+
 	// Add middleware for request logging and panic recovery
-	e.Use(middleware.Logger())
+	e.Use(middleware.RequestID())
+
+	e.Use(logger.Middleware) // Request logger with request_id, route and user
+
+	e.Use(metrics.Middleware) // Outside Recover so panics are counted as 500
+
+	e.Use(ratelimit.Middleware(systemRequest)) // Read and write budgets per API token or client IP
 
 	e.Use(middleware.Recover())
 
+	e.Use(warmupGate)
+
 	// Team endpoints
-	e.POST("/team/add", handler.AddTeam)
+	e.POST("/team/add", handler.AddTeam, bodyLimit(cfg.Server.TeamBodyLimit))
 
 	e.GET("/team/get", handler.GetTeam)
 
+	e.GET("/team/list", handler.ListTeams)
+
+	e.POST("/team/setPolicy", handler.SetTeamPolicy)
+
+	e.GET("/team/policy", handler.GetTeamPolicy)
+
 	// Users endpoints
 	e.POST("/users/setIsActive", handler.SetUserIsActive)
 
+	e.POST("/users/setProfile", handler.SetUserProfile)
+
 	e.GET("/users/getReview", handler.GetUserReview)
 
+	e.GET("/users/reviewStream", handler.GetUserReviewStream)
+
 	// PullRequest endpoints
 	e.POST("/pullRequest/create", handler.CreatePullRequest)
 
 	e.POST("/pullRequest/merge", handler.MergePullRequest)
 
+	e.POST("/pullRequest/createBatch", handler.CreatePullRequestBatch)
+
+	e.POST("/pullRequest/mergeBatch", handler.MergePullRequestBatch)
+
 	e.POST("/pullRequest/reassign", handler.ReassignPullRequest)
 
+	e.POST("/pullRequest/decline", handler.DeclinePullRequest)
+
+	e.GET("/pullRequest/list", handler.ListPullRequests)
+
+	e.GET("/pullRequest/stale", handler.ListStaleReviews)
+
+	e.GET("/pullRequest/history", handler.GetPullRequestHistory)
+
+	// Stats endpoints
+	e.GET("/stats", handler.GetStats)
+
+	// Admin endpoints, they read and replace all data so they need the admin token
+	admin := e.Group("/admin", adminAuth(cfg.Server.AdminToken))
+
+	admin.GET("/export", handler.ExportData)
+
+	admin.POST("/import", handler.ImportData, bodyLimit(cfg.Server.ImportBodyLimit))
+
 	// System endpoints
 	e.GET("/health", handler.Health)
 
+	e.GET("/health/live", handler.Live)
+
+	e.GET("/health/ready", handler.Ready)
+
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -50,3 +113,79 @@ func StartServer(ctx context.Context) *echo.Echo {
 	return e
 
 }
+
+// warmupGate answers 503 to API requests until cache warm-up finished and services are initialized
+// Probes, metrics and docs stay available so orchestrators can watch the warm-up
+func warmupGate(next echo.HandlerFunc) echo.HandlerFunc {
+
+	return func(c echo.Context) error {
+
+		if health.WarmedUp() || systemRequest(c) {
+
+			return next(c)
+
+		}
+
+		return c.JSON(http.StatusServiceUnavailable, errs.Unavailable())
+
+	}
+
+}
+
+// systemRequest reports probes, metrics and docs, which skip tracing, rate limits and the warm-up gate
+func systemRequest(c echo.Context) bool {
+
+	path := c.Request().URL.Path
+
+	return strings.HasPrefix(path, "/health") || path == "/metrics" || strings.HasPrefix(path, "/swagger/")
+
+}
+
+// bodyLimit answers 413 PAYLOAD_TOO_LARGE to bodies over limit bytes
+// Bodies without Content-Length are cut at the limit and rejected while binding
+func bodyLimit(limit int64) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			req := c.Request()
+
+			if req.ContentLength > limit {
+
+				return c.JSON(http.StatusRequestEntityTooLarge, errs.PayloadTooLarge())
+
+			}
+
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
+			return next(c)
+
+		}
+
+	}
+
+}
+
+// adminAuth accepts only requests carrying token as a bearer token, an empty token rejects every request
+func adminAuth(token string) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			got, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+
+				return c.JSON(http.StatusUnauthorized, errs.Unauthorized())
+
+			}
+
+			return next(c)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+
+	serve := func(token string, header string) int {
+
+		e := echo.New()
+
+		e.GET("/admin/export", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, adminAuth(token))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+
+		if header != "" {
+
+			req.Header.Set(echo.HeaderAuthorization, header)
+
+		}
+
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		return rec.Code
+
+	}
+
+	assert.Equal(t, http.StatusOK, serve("secret", "Bearer secret"))
+
+	assert.Equal(t, http.StatusUnauthorized, serve("secret", ""))
+
+	assert.Equal(t, http.StatusUnauthorized, serve("secret", "Bearer guess"))
+
+	assert.Equal(t, http.StatusUnauthorized, serve("", "Bearer "), "an unset token closes the routes")
+
+}
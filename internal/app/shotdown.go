@@ -4,50 +4,115 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
 
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/health"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
 )
 
-// HandleSignals sets up signal handling for graceful shutdown
-func HandleSignals(cancel context.CancelFunc) {
+// HandleSignals cancels ctx on SIGINT or SIGTERM and reloads the configuration on SIGHUP
+func HandleSignals(cancel context.CancelFunc, reload func() error) {
 
 	go func() {
 
 		c := make(chan os.Signal, 1) // Create channel to receive OS signals
 
-		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM) // Register for interrupt and terminate signals
+		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP) // Register for interrupt, terminate and reload signals
 
-		sig := <-c // Wait for signal
+		for sig := range c {
 
-		logger.Info("Caught signal", sig)
+			logger.Info("Caught signal", "signal", sig.String())
 
-		cancel()
+			if sig == syscall.SIGHUP {
+
+				_ = reload() // Errors are logged by reload, the service keeps running
+
+				continue
+
+			}
+
+			cancel()
+
+			return
+
+		}
 
 	}()
 
 }
 
 // GracefulShutdown performs orderly shutdown of all services
-func GracefulShutdown(e *echo.Echo, db *pgxpool.Pool) {
+func GracefulShutdown(e *echo.Echo, g *grpc.Server, db *pgxpool.Pool, drain time.Duration) {
 
 	logger.Info("Shutting down services")
 
+	health.MarkDraining() // Readiness turns 503 so load balancers stop sending traffic
+
+	if drain > 0 {
+
+		logger.Info("Draining before shutdown", "delay", drain.String())
+
+		time.Sleep(drain)
+
+	}
+
 	// Create shutdown context with 5 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 
 	defer cancel()
 
-	// Shutdown HTTP server
-	if err := e.Shutdown(ctx); err != nil {
+	var wg sync.WaitGroup
 
-		logger.Error(err, "HTTP server shutdown failed")
+	wg.Add(2)
 
-	}
+	// Shutdown HTTP and gRPC servers together so neither keeps serving alone
+	go func() {
+
+		defer wg.Done()
+
+		if err := e.Shutdown(ctx); err != nil {
+
+			logger.Error(err, "HTTP server shutdown failed")
+
+		}
+
+	}()
+
+	go func() {
+
+		defer wg.Done()
+
+		stopped := make(chan struct{})
+
+		go func() {
+
+			g.GracefulStop() // Wait for in-flight RPCs
+
+			close(stopped)
+
+		}()
+
+		select {
+
+		case <-stopped:
+
+		case <-ctx.Done():
+
+			logger.Info("gRPC server graceful stop timed out, forcing")
+
+			g.Stop()
+
+		}
+
+	}()
+
+	wg.Wait()
 
 	// Close database connection pool
 	db.Close()
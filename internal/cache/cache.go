@@ -2,83 +2,148 @@ package cache
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 )
 
-var UserCache lruCache //cache for users
+var UserCache *Sharded[string, models.User] //cache for users
 
-var TeamCache lruCache //cache for teams
+var TeamCache *Sharded[string, models.Team] //cache for teams
 
-var PRcache lruCache //cache for PRs
+var PRcache *Sharded[string, models.PullRequest] //cache for PRs
 
 // node in LRU cache
-type lruNode struct {
-	key string
+type lruNode[K comparable, V any] struct {
+	key K
 
-	value interface{}
+	value V
 
-	prev *lruNode
+	expiresAt time.Time // zero means no expiry
 
-	next *lruNode
+	prev *lruNode[K, V]
+
+	next *lruNode[K, V]
 }
 
-// simple LRU cache
-type lruCache struct {
+// Cache is a typed LRU cache with optional per-entry TTL
+type Cache[K comparable, V any] struct {
+	name string // label for metrics, empty disables them
+
 	capacity int
 
-	store map[string]*lruNode
+	ttl atomic.Int64 // default TTL for Set in nanoseconds, zero means no expiry, changed on config reload
+
+	onEvict func(K, V) // called after an entry is dropped by capacity or TTL
+
+	now func() time.Time
+
+	reported int // size last added to the size gauge, shards of one cache share the gauge
+
+	store map[K]*lruNode[K, V]
 
-	head *lruNode
+	head *lruNode[K, V]
 
-	tail *lruNode
+	tail *lruNode[K, V]
 
 	mu sync.Mutex
 }
 
+// Option configures a Cache
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// WithTTL sets the default time to live for entries added by Set
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+
+	return func(c *Cache[K, V]) { c.ttl.Store(int64(ttl)) }
+
+}
+
+// WithOnEvict registers a callback for entries evicted by capacity or expired by TTL
+// Delete and Purge do not trigger it
+func WithOnEvict[K comparable, V any](fn func(K, V)) Option[K, V] {
+
+	return func(c *Cache[K, V]) { c.onEvict = fn }
+
+}
+
+// WithMetrics exports hit, miss, eviction and size counters under the given cache name
+func WithMetrics[K comparable, V any](name string) Option[K, V] {
+
+	return func(c *Cache[K, V]) { c.name = name }
+
+}
+
 // constructor
-func NewOrderCache(cap int) lruCache {
+func New[K comparable, V any](cap int, opts ...Option[K, V]) *Cache[K, V] {
 
-	return lruCache{
+	c := &Cache[K, V]{
 
 		capacity: cap,
 
-		store: make(map[string]*lruNode),
+		now: time.Now,
+
+		store: make(map[K]*lruNode[K, V]),
 	}
 
-}
+	for _, opt := range opts {
 
-// move node to front (most recently used)
-func (c *lruCache) moveToFront(node *lruNode) {
+		opt(c)
 
-	if c.head == node {
+	}
 
-		return
+	return c
 
-	}
+}
+
+// unlink node from the list
+func (c *Cache[K, V]) unlink(node *lruNode[K, V]) {
 
-	// unlink node
 	if node.prev != nil {
 
 		node.prev.next = node.next
 
+	} else {
+
+		c.head = node.next
+
 	}
 
 	if node.next != nil {
 
 		node.next.prev = node.prev
 
-	}
-
-	if c.tail == node {
+	} else {
 
 		c.tail = node.prev
 
 	}
 
-	// put node at head
 	node.prev = nil
 
+	node.next = nil
+
+}
+
+// move node to front (most recently used)
+func (c *Cache[K, V]) moveToFront(node *lruNode[K, V]) {
+
+	if c.head == node {
+
+		return
+
+	}
+
+	if node.prev != nil { // node is already linked, new nodes are not
+
+		c.unlink(node)
+
+	}
+
+	// put node at head
 	node.next = c.head
 
 	if c.head != nil {
@@ -97,44 +162,137 @@ func (c *lruCache) moveToFront(node *lruNode) {
 
 }
 
-// add new order to cache
-func (c *lruCache) Set(key string, val interface{}) {
+// remove node from list and map
+func (c *Cache[K, V]) remove(node *lruNode[K, V]) {
+
+	c.unlink(node)
+
+	delete(c.store, node.key)
+
+}
+
+// expired reports whether the node TTL has passed
+func (c *Cache[K, V]) expired(node *lruNode[K, V]) bool {
+
+	return !node.expiresAt.IsZero() && !c.now().Before(node.expiresAt)
+
+}
+
+// Set adds or updates a value using the default TTL
+func (c *Cache[K, V]) Set(key K, val V) {
+
+	c.SetWithTTL(key, val, time.Duration(c.ttl.Load()))
+
+}
+
+// SetTTL changes the default TTL of later Set calls, entries already stored keep their expiry
+func (c *Cache[K, V]) SetTTL(ttl time.Duration) {
+
+	c.ttl.Store(int64(ttl))
+
+}
+
+// SetWithTTL adds or updates a value that expires after ttl, zero ttl means no expiry
+func (c *Cache[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+
+	var expiresAt time.Time
+
+	if ttl > 0 {
+
+		expiresAt = c.now().Add(ttl)
+
+	}
 
 	c.mu.Lock()
 
-	defer c.mu.Unlock()
+	node, ok := c.store[key]
+
+	if ok {
+
+		node.value = val
 
-	node := &lruNode{key: key, value: val}
+		node.expiresAt = expiresAt
 
-	c.store[key] = node
+	} else {
+
+		node = &lruNode[K, V]{key: key, value: val, expiresAt: expiresAt}
+
+		c.store[key] = node
+
+	}
 
 	c.moveToFront(node)
 
+	var evicted []*lruNode[K, V]
+
 	// remove LRU if over capacity
-	if len(c.store) > c.capacity {
+	for len(c.store) > c.capacity && c.tail != nil {
+
+		evicted = append(evicted, c.tail)
+
+		c.remove(c.tail)
+
+	}
+
+	c.updateSize()
+
+	c.mu.Unlock()
+
+	c.evicted(evicted)
+
+}
+
+// Get returns a value if present and not expired
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+
+	c.mu.Lock()
+
+	node, ok := c.store[key]
+
+	if ok && c.expired(node) {
+
+		c.remove(node)
+
+		c.updateSize()
+
+		c.mu.Unlock()
 
-		delete(c.store, c.tail.key)
+		c.evicted([]*lruNode[K, V]{node})
 
-		if c.tail.prev != nil {
+		c.miss()
 
-			c.tail = c.tail.prev
+		var zero V
 
-			c.tail.next = nil
+		return zero, false
 
-		} else {
-			// only one node
-			c.head = nil
+	}
+
+	if !ok {
+
+		c.mu.Unlock()
 
-			c.tail = nil
+		c.miss()
 
-		}
+		var zero V
+
+		return zero, false
 
 	}
 
+	c.moveToFront(node) // mark as recently used
+
+	val := node.value
+
+	c.mu.Unlock()
+
+	c.hit()
+
+	return val, true
+
 }
 
-// get order from cache
-func (c *lruCache) Get(key string) (interface{}, bool) {
+// Delete removes a key from the cache
+func (c *Cache[K, V]) Delete(key K) {
 
 	c.mu.Lock()
 
@@ -142,22 +300,133 @@ func (c *lruCache) Get(key string) (interface{}, bool) {
 
 	if node, ok := c.store[key]; ok {
 
-		c.moveToFront(node) // mark as recently used
+		c.remove(node)
+
+		c.updateSize()
+
+	}
+
+}
+
+// Purge removes all entries from the cache
+func (c *Cache[K, V]) Purge() {
+
+	c.mu.Lock()
+
+	defer c.mu.Unlock()
+
+	c.store = make(map[K]*lruNode[K, V])
+
+	c.head = nil
+
+	c.tail = nil
+
+	c.updateSize()
+
+}
+
+// Len returns the number of entries, including expired ones not yet collected
+func (c *Cache[K, V]) Len() int {
+
+	c.mu.Lock()
+
+	defer c.mu.Unlock()
+
+	return len(c.store)
+
+}
+
+// evicted reports dropped entries to metrics and the eviction callback, called without lock
+func (c *Cache[K, V]) evicted(nodes []*lruNode[K, V]) {
+
+	if len(nodes) == 0 {
+
+		return
+
+	}
+
+	if c.name != "" {
+
+		metrics.CacheEvictions.WithLabelValues(c.name).Add(float64(len(nodes)))
+
+	}
+
+	if c.onEvict == nil {
+
+		return
+
+	}
 
-		return node.value, true
+	for _, node := range nodes {
+
+		c.onEvict(node.key, node.value)
 
 	}
 
-	return nil, false
+}
+
+func (c *Cache[K, V]) hit() {
+
+	if c.name != "" {
+
+		metrics.CacheHits.WithLabelValues(c.name).Inc()
+
+	}
+
+}
+
+func (c *Cache[K, V]) miss() {
+
+	if c.name != "" {
+
+		metrics.CacheMisses.WithLabelValues(c.name).Inc()
+
+	}
 
 }
 
-func InitCache() {
+// updateSize must be called with lock held
+func (c *Cache[K, V]) updateSize() {
+
+	if c.name != "" {
+
+		metrics.CacheSize.WithLabelValues(c.name).Add(float64(len(c.store) - c.reported))
+
+		c.reported = len(c.store)
+
+	}
+
+}
+
+func InitCache(cfg config.Cache) {
+
+	UserCache = NewSharded(cfg.Cap, cfg.Shards, HashString,
+
+		WithTTL[string, models.User](cfg.TTL), WithMetrics[string, models.User]("users"))
+
+	TeamCache = NewSharded(cfg.Cap, cfg.Shards, HashString,
+
+		WithTTL[string, models.Team](cfg.TTL), WithMetrics[string, models.Team]("teams"))
+
+	PRcache = NewSharded(cfg.Cap, cfg.Shards, HashString,
+
+		WithTTL[string, models.PullRequest](cfg.TTL), WithMetrics[string, models.PullRequest]("pull_requests"))
+
+}
+
+// SetTTL changes the default TTL of the service caches, used on config reload
+func SetTTL(ttl time.Duration) {
+
+	if UserCache == nil { // Caches are created by InitCache
+
+		return
+
+	}
 
-	UserCache = NewOrderCache(config.CacheCap)
+	UserCache.SetTTL(ttl)
 
-	TeamCache = NewOrderCache(config.CacheCap)
+	TeamCache.SetTTL(ttl)
 
-	PRcache = NewOrderCache(config.CacheCap)
+	PRcache.SetTTL(ttl)
 
 }
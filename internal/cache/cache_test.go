@@ -3,13 +3,14 @@ package cache
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestLRUCache_BasicOperations(t *testing.T) {
 
-	cache := NewOrderCache(3)
+	cache := New[string, string](3)
 
 	cache.Set("key1", "value1")
 
@@ -23,13 +24,13 @@ func TestLRUCache_BasicOperations(t *testing.T) {
 
 	assert.False(t, found)
 
-	assert.Nil(t, val)
+	assert.Empty(t, val)
 
 }
 
 func TestLRUCache_Capacity(t *testing.T) {
 
-	cache := NewOrderCache(2)
+	cache := New[string, string](2)
 
 	cache.Set("key1", "value1")
 
@@ -41,7 +42,7 @@ func TestLRUCache_Capacity(t *testing.T) {
 
 	assert.False(t, found)
 
-	assert.Nil(t, val)
+	assert.Empty(t, val)
 
 	val, found = cache.Get("key2")
 
@@ -59,7 +60,7 @@ func TestLRUCache_Capacity(t *testing.T) {
 
 func TestLRUCache_LRUEviction(t *testing.T) {
 
-	cache := NewOrderCache(3)
+	cache := New[string, string](3)
 
 	cache.Set("key1", "value1")
 
@@ -75,7 +76,7 @@ func TestLRUCache_LRUEviction(t *testing.T) {
 
 	assert.False(t, found)
 
-	assert.Nil(t, val)
+	assert.Empty(t, val)
 
 	val, found = cache.Get("key1")
 
@@ -99,7 +100,7 @@ func TestLRUCache_LRUEviction(t *testing.T) {
 
 func TestLRUCache_UpdateExistingKey(t *testing.T) {
 
-	cache := NewOrderCache(2)
+	cache := New[string, string](2)
 
 	cache.Set("key1", "value1")
 
@@ -115,7 +116,7 @@ func TestLRUCache_UpdateExistingKey(t *testing.T) {
 
 func TestLRUCache_ConcurrentAccess(t *testing.T) {
 
-	cache := NewOrderCache(100)
+	cache := New[string, string](100)
 
 	numGoroutines := 10
 
@@ -169,7 +170,7 @@ func TestLRUCache_ConcurrentAccess(t *testing.T) {
 
 func TestLRUCache_EdgeCases(t *testing.T) {
 
-	cache := NewOrderCache(0)
+	cache := New[string, string](0)
 
 	cache.Set("key1", "value1")
 
@@ -177,9 +178,9 @@ func TestLRUCache_EdgeCases(t *testing.T) {
 
 	assert.False(t, found)
 
-	assert.Nil(t, val)
+	assert.Empty(t, val)
 
-	cache = NewOrderCache(1)
+	cache = New[string, string](1)
 
 	cache.Set("key1", "value1")
 
@@ -199,7 +200,7 @@ func TestLRUCache_EdgeCases(t *testing.T) {
 
 func TestLRUCache_OrderAfterAccess(t *testing.T) {
 
-	cache := NewOrderCache(3)
+	cache := New[string, string](3)
 
 	cache.Set("key1", "value1")
 
@@ -230,3 +231,147 @@ func TestLRUCache_OrderAfterAccess(t *testing.T) {
 	assert.True(t, found)
 
 }
+
+func TestLRUCache_TTL(t *testing.T) {
+
+	now := time.Unix(0, 0)
+
+	cache := New(3, WithTTL[string, string](time.Minute))
+
+	cache.now = func() time.Time { return now }
+
+	cache.Set("key1", "value1")
+
+	cache.SetWithTTL("key2", "value2", 0)
+
+	now = now.Add(30 * time.Second)
+
+	val, found := cache.Get("key1")
+
+	assert.True(t, found)
+
+	assert.Equal(t, "value1", val)
+
+	now = now.Add(30 * time.Second)
+
+	val, found = cache.Get("key1")
+
+	assert.False(t, found)
+
+	assert.Empty(t, val)
+
+	assert.Equal(t, 1, cache.Len())
+
+	val, found = cache.Get("key2")
+
+	assert.True(t, found)
+
+	assert.Equal(t, "value2", val)
+
+}
+
+func TestLRUCache_SetTTL(t *testing.T) {
+
+	now := time.Unix(0, 0)
+
+	cache := New(3, WithTTL[string, string](time.Minute))
+
+	cache.now = func() time.Time { return now }
+
+	cache.Set("old", "value")
+
+	cache.SetTTL(time.Second)
+
+	cache.Set("new", "value")
+
+	now = now.Add(2 * time.Second)
+
+	_, found := cache.Get("old")
+
+	assert.True(t, found) // Stored entries keep their expiry
+
+	_, found = cache.Get("new")
+
+	assert.False(t, found)
+
+}
+
+func TestLRUCache_DeleteAndPurge(t *testing.T) {
+
+	cache := New[string, string](3)
+
+	cache.Set("key1", "value1")
+
+	cache.Set("key2", "value2")
+
+	cache.Set("key3", "value3")
+
+	cache.Delete("key2")
+
+	cache.Delete("nonexistent")
+
+	_, found := cache.Get("key2")
+
+	assert.False(t, found)
+
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Set("key4", "value4")
+
+	cache.Set("key5", "value5")
+
+	_, found = cache.Get("key1")
+
+	assert.False(t, found)
+
+	cache.Purge()
+
+	assert.Equal(t, 0, cache.Len())
+
+	_, found = cache.Get("key5")
+
+	assert.False(t, found)
+
+	cache.Set("key6", "value6")
+
+	val, found := cache.Get("key6")
+
+	assert.True(t, found)
+
+	assert.Equal(t, "value6", val)
+
+}
+
+func TestLRUCache_OnEvict(t *testing.T) {
+
+	now := time.Unix(0, 0)
+
+	evicted := map[string]string{}
+
+	cache := New(2, WithOnEvict(func(key string, val string) { evicted[key] = val }))
+
+	cache.now = func() time.Time { return now }
+
+	cache.Set("key1", "value1")
+
+	cache.Set("key2", "value2")
+
+	cache.Set("key3", "value3")
+
+	assert.Equal(t, map[string]string{"key1": "value1"}, evicted)
+
+	cache.SetWithTTL("key4", "value4", time.Second)
+
+	now = now.Add(time.Second)
+
+	cache.Get("key4")
+
+	assert.Equal(t, map[string]string{"key1": "value1", "key2": "value2", "key4": "value4"}, evicted)
+
+	cache.Delete("key3")
+
+	cache.Purge()
+
+	assert.Len(t, evicted, 3)
+
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+var stringSeed = maphash.MakeSeed()
+
+// HashString is the shard hash for string keys
+func HashString(key string) uint64 {
+
+	return maphash.String(stringSeed, key)
+
+}
+
+// Sharded is a Cache split into independently locked partitions chosen by key hash
+// Each shard keeps its own LRU order, so eviction is least recently used within a shard
+type Sharded[K comparable, V any] struct {
+	shards []*Cache[K, V]
+
+	hash func(K) uint64
+}
+
+// constructor, capacity is divided evenly between shards
+func NewSharded[K comparable, V any](cap int, shards int, hash func(K) uint64, opts ...Option[K, V]) *Sharded[K, V] {
+
+	if shards > cap {
+
+		shards = cap // no point in shards that cannot hold anything
+
+	}
+
+	if shards < 1 {
+
+		shards = 1
+
+	}
+
+	s := &Sharded[K, V]{
+
+		shards: make([]*Cache[K, V], shards),
+
+		hash: hash,
+	}
+
+	for i := range s.shards {
+
+		shardCap := cap / shards
+
+		if i < cap%shards {
+
+			shardCap++
+
+		}
+
+		s.shards[i] = New(shardCap, opts...)
+
+	}
+
+	return s
+
+}
+
+// shard picks the partition for key
+func (s *Sharded[K, V]) shard(key K) *Cache[K, V] {
+
+	return s.shards[s.hash(key)%uint64(len(s.shards))]
+
+}
+
+// Set adds or updates a value using the default TTL
+func (s *Sharded[K, V]) Set(key K, val V) {
+
+	s.shard(key).Set(key, val)
+
+}
+
+// SetWithTTL adds or updates a value that expires after ttl, zero ttl means no expiry
+func (s *Sharded[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+
+	s.shard(key).SetWithTTL(key, val, ttl)
+
+}
+
+// SetTTL changes the default TTL of every shard
+func (s *Sharded[K, V]) SetTTL(ttl time.Duration) {
+
+	for _, shard := range s.shards {
+
+		shard.SetTTL(ttl)
+
+	}
+
+}
+
+// Get returns a value if present and not expired
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+
+	return s.shard(key).Get(key)
+
+}
+
+// Delete removes a key from the cache
+func (s *Sharded[K, V]) Delete(key K) {
+
+	s.shard(key).Delete(key)
+
+}
+
+// Purge removes all entries from every shard
+func (s *Sharded[K, V]) Purge() {
+
+	for _, shard := range s.shards {
+
+		shard.Purge()
+
+	}
+
+}
+
+// Len returns the number of entries across shards
+func (s *Sharded[K, V]) Len() int {
+
+	total := 0
+
+	for _, shard := range s.shards {
+
+		total += shard.Len()
+
+	}
+
+	return total
+
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedCache_BasicOperations(t *testing.T) {
+
+	cache := NewSharded[string, string](64, 8, HashString)
+
+	for i := 0; i < 64; i++ {
+
+		cache.Set(fmt.Sprint("key", i), fmt.Sprint("value", i))
+
+	}
+
+	for i := 0; i < 64; i += 7 {
+
+		cache.Delete(fmt.Sprint("key", i))
+
+	}
+
+	for i := 0; i < 64; i++ {
+
+		val, found := cache.Get(fmt.Sprint("key", i))
+
+		if i%7 == 0 {
+
+			assert.False(t, found)
+
+			continue
+
+		}
+
+		if found {
+
+			assert.Equal(t, fmt.Sprint("value", i), val)
+
+		}
+
+	}
+
+	cache.Purge()
+
+	assert.Equal(t, 0, cache.Len())
+
+}
+
+func TestShardedCache_Capacity(t *testing.T) {
+
+	cache := NewSharded[string, string](10, 4, HashString)
+
+	for i := 0; i < 100; i++ {
+
+		cache.Set(fmt.Sprint("key", i), "value")
+
+	}
+
+	assert.LessOrEqual(t, cache.Len(), 10)
+
+	cache = NewSharded[string, string](2, 16, HashString)
+
+	assert.Len(t, cache.shards, 2)
+
+	cache = NewSharded[string, string](0, 16, HashString)
+
+	cache.Set("key1", "value1")
+
+	_, found := cache.Get("key1")
+
+	assert.False(t, found)
+
+}
+
+func TestShardedCache_ConcurrentAccess(t *testing.T) {
+
+	cache := NewSharded[string, string](1000, 16, HashString)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+
+		wg.Add(1)
+
+		go func(goroutineID int) {
+
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+
+				key := fmt.Sprint(goroutineID, "_", j)
+
+				cache.Set(key, key+"_value")
+
+				val, found := cache.Get(key)
+
+				if found {
+
+					assert.Equal(t, key+"_value", val)
+
+				}
+
+			}
+
+		}(i)
+
+	}
+
+	wg.Wait()
+
+}
+
+// store is the part of the cache API exercised by benchmarks
+type store interface {
+	Set(string, string)
+
+	Get(string) (string, bool)
+}
+
+// benchmarkParallel runs b.N mixed operations (9 reads per write) split across goroutines
+func benchmarkParallel(b *testing.B, c store, goroutines int) {
+
+	keys := make([]string, 10000)
+
+	for i := range keys {
+
+		keys[i] = fmt.Sprint("key", i)
+
+		c.Set(keys[i], "value")
+
+	}
+
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < goroutines; g++ {
+
+		wg.Add(1)
+
+		go func(g int) {
+
+			defer wg.Done()
+
+			for i := g; i < b.N; i += goroutines {
+
+				key := keys[(i*7919)%len(keys)]
+
+				if i%10 == 0 {
+
+					c.Set(key, "value")
+
+				} else {
+
+					c.Get(key)
+
+				}
+
+			}
+
+		}(g)
+
+	}
+
+	wg.Wait()
+
+}
+
+func BenchmarkCache(b *testing.B) {
+
+	for _, goroutines := range []int{1, 8, 64} {
+
+		b.Run(fmt.Sprintf("single/goroutines=%d", goroutines), func(b *testing.B) {
+
+			benchmarkParallel(b, New[string, string](5000), goroutines)
+
+		})
+
+		b.Run(fmt.Sprintf("sharded/goroutines=%d", goroutines), func(b *testing.B) {
+
+			benchmarkParallel(b, NewSharded[string, string](5000, 16, HashString), goroutines)
+
+		})
+
+	}
+
+}
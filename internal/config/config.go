@@ -1,51 +1,855 @@
 package config
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"gopkg.in/yaml.v3"
 )
 
+// Config holds every setting of the service, it is loaded once in main and passed down explicitly
+type Config struct {
+	Server Server `yaml:"server"`
+
+	Log Log `yaml:"log"`
+
+	Database Database `yaml:"database"`
+
+	Cache Cache `yaml:"cache"`
+
+	Assignment Assignment `yaml:"assignment"`
+
+	Trace Trace `yaml:"trace"`
+
+	RateLimit RateLimit `yaml:"rate_limit"`
+
+	SLA SLA `yaml:"sla"`
+
+	File string `yaml:"-"` // YAML file the config was loaded from, watched for reload
+}
+
+type Server struct {
+	HTTPAddr string `yaml:"http_addr"`
+
+	GRPCAddr string `yaml:"grpc_addr"`
+
+	HealthTimeout time.Duration `yaml:"health_timeout"` // database check of /health/ready
+
+	ShutdownDrain time.Duration `yaml:"shutdown_drain"` // how long /health/ready answers 503 before servers stop
+
+	TeamBodyLimit int64 `yaml:"team_body_limit"` // bytes accepted by /team/add
+
+	ImportBodyLimit int64 `yaml:"import_body_limit"` // bytes accepted by /admin/import
+
+	AdminToken string `yaml:"admin_token"` // bearer token of /admin/*, empty disables them
+}
+
+type Log struct {
+	Format string `yaml:"format"`
+
+	Level string `yaml:"level"`
+}
+
+type Database struct {
+	URL string `yaml:"url"`
+
+	TestURL string `yaml:"test_url"`
+
+	MaxConns int32 `yaml:"max_conns"`
+
+	MinConns int32 `yaml:"min_conns"`
+
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+
+	TransferTimeout time.Duration `yaml:"transfer_timeout"` // whole export or import of /admin/*
+
+	BatchTimeout time.Duration `yaml:"batch_timeout"` // whole atomic write of several pull requests
+
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+
+	MigrationPath string `yaml:"migration_path"`
+}
+
+type Cache struct {
+	Cap int `yaml:"cap"`
+
+	Shards int `yaml:"shards"`
+
+	TTL time.Duration `yaml:"ttl"` // 0 keeps entries until evicted
+
+	Warmup string `yaml:"warmup"`
+
+	WarmupLimit int `yaml:"warmup_limit"` // entries loaded by the recent policy, 0 means Cap
+}
+
+type Assignment struct {
+	Reviewers int `yaml:"reviewers"` // reviewers picked for a new pull request
+
+	Strategy string `yaml:"strategy"`
+
+	// Every assignment of a candidate to pull requests of the same author within PairWindow
+	// counts as PairWeight extra reviews against them, so the same pairs are not picked again and again
+	PairWindow time.Duration `yaml:"pair_window"`
+
+	PairWeight float64 `yaml:"pair_weight"` // 0 disables the penalty
+}
+
+// RateLimit sets token buckets per API token or client IP, a zero rate disables the budget
+type RateLimit struct {
+	ReadRate float64 `yaml:"read_rate"` // GET requests per second
+
+	ReadBurst int `yaml:"read_burst"`
+
+	WriteRate float64 `yaml:"write_rate"` // mutating requests per second
+
+	WriteBurst int `yaml:"write_burst"`
+
+	Tokens string `yaml:"tokens"` // comma separated API tokens with their own budget, other callers are limited by IP
+
+	// Comma separated CIDRs of proxies whose X-Forwarded-For is trusted, empty uses the connection address
+	TrustedProxies string `yaml:"trusted_proxies"`
+
+	MaxClients int `yaml:"max_clients"` // tracked clients per budget, new clients beyond it share one bucket
+}
+
+// SLA sets how long assigned reviews may wait and how the reminder scheduler runs
+type SLA struct {
+	Default time.Duration `yaml:"default"` // review SLA of teams without their own policy
+
+	CheckInterval time.Duration `yaml:"check_interval"` // how often overdue reviews are looked up, 0 disables reminders
+
+	RemindEvery time.Duration `yaml:"remind_every"` // pause between reminders about the same review
+
+	BatchSize int `yaml:"batch_size"` // reminders sent per check
+}
+
+type Trace struct {
+	Exporter string `yaml:"exporter"`
+
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Cache warm-up policies
+const (
+	WarmupAll = "all" // load everything that fits into the cache
+
+	WarmupRecent = "recent" // load the CACHE_WARMUP_LIMIT most recent entries
+
+	WarmupOpen = "open" // load only OPEN pull requests
+
+	WarmupNone = "none" // skip warm-up
+)
+
+// Reviewer assignment strategies
+const (
+	StrategyOrdered = "ordered" // first active members in team order
+
+	StrategyRandom = "random" // random active members
+)
+
+// Log formats and trace exporters accepted by validation
 var (
-	PostgresURL string
+	logFormats = []string{"json", "console"}
 
-	TestPostgresURL string
+	logLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "panic", "disabled"}
 
-	CacheCap int
+	warmups = []string{WarmupAll, WarmupRecent, WarmupOpen, WarmupNone}
 
-	PostgresTimeOut time.Duration
+	traceExporters = []string{"none", "stdout", "otlp"}
 
-	MigrationPath string
+	strategies = []string{StrategyOrdered, StrategyRandom}
 )
 
-func VarsInit() {
+// Default returns the configuration used when nothing overrides it
+func Default() Config {
 
-	PostgresURL = os.Getenv("POSTGRES_URL")
+	return Config{
 
-	TestPostgresURL = os.Getenv("TEST_POSTGRES_URL")
+		Server: Server{HTTPAddr: ":8080", GRPCAddr: ":9090", HealthTimeout: time.Second, TeamBodyLimit: 1 << 20, ImportBodyLimit: 256 << 20},
 
-	var err error
+		Log: Log{Format: "json", Level: "info"},
 
-	CacheCap, err = strconv.Atoi(os.Getenv("CACHE_CAP"))
+		Database: Database{MaxConns: 10, QueryTimeout: 3 * time.Second, TransferTimeout: 10 * time.Minute, BatchTimeout: 30 * time.Second, ConnectTimeout: 5 * time.Second, MigrationPath: "./migrations"},
 
-	if err != nil {
+		Cache: Cache{Cap: 1000, Shards: 16, Warmup: WarmupAll},
+
+		Assignment: Assignment{Reviewers: 2, Strategy: StrategyOrdered, PairWindow: 30 * 24 * time.Hour, PairWeight: 1},
+
+		Trace: Trace{Exporter: "none", SampleRatio: 1},
 
-		logger.Fatal(err, "CACHE_CAP is not number")
+		RateLimit: RateLimit{ReadRate: 50, ReadBurst: 100, WriteRate: 10, WriteBurst: 20, MaxClients: 10000},
 
+		SLA: SLA{Default: 24 * time.Hour, CheckInterval: time.Minute, RemindEvery: 4 * time.Hour, BatchSize: 100},
 	}
 
-	PostgresTimeOutSec, err := strconv.Atoi(os.Getenv("POSTGRES_TIMEOUT"))
+}
+
+// setting binds one field to its environment variable and command line flag
+type setting struct {
+	env string
+
+	flag string
+
+	usage string
+
+	field func(c *Config) interface{} // pointer to the field
+}
+
+var settings = []setting{
+
+	{"HTTP_ADDR", "http-addr", "HTTP listen address", func(c *Config) interface{} { return &c.Server.HTTPAddr }},
+
+	{"GRPC_ADDR", "grpc-addr", "gRPC listen address", func(c *Config) interface{} { return &c.Server.GRPCAddr }},
+
+	{"HEALTH_TIMEOUT", "health-timeout", "database check timeout of /health/ready", func(c *Config) interface{} { return &c.Server.HealthTimeout }},
+
+	{"SHUTDOWN_DRAIN", "shutdown-drain", "delay between failing readiness and stopping servers", func(c *Config) interface{} { return &c.Server.ShutdownDrain }},
+
+	{"TEAM_BODY_LIMIT", "team-body-limit", "bytes accepted by /team/add", func(c *Config) interface{} { return &c.Server.TeamBodyLimit }},
+
+	{"IMPORT_BODY_LIMIT", "import-body-limit", "bytes accepted by /admin/import", func(c *Config) interface{} { return &c.Server.ImportBodyLimit }},
+
+	{"ADMIN_TOKEN", "", "", func(c *Config) interface{} { return &c.Server.AdminToken }}, // secrets are kept out of flags
+
+	{"LOG_FORMAT", "log-format", "log format: json or console", func(c *Config) interface{} { return &c.Log.Format }},
+
+	{"LOG_LEVEL", "log-level", "log level: debug, info, warn, error", func(c *Config) interface{} { return &c.Log.Level }},
+
+	{"POSTGRES_URL", "postgres-url", "Postgres connection URL", func(c *Config) interface{} { return &c.Database.URL }},
+
+	{"TEST_POSTGRES_URL", "", "", func(c *Config) interface{} { return &c.Database.TestURL }},
+
+	{"DB_MAX_CONNS", "db-max-conns", "maximum connections in the pool", func(c *Config) interface{} { return &c.Database.MaxConns }},
+
+	{"DB_MIN_CONNS", "db-min-conns", "connections kept open in the pool", func(c *Config) interface{} { return &c.Database.MinConns }},
+
+	{"POSTGRES_TIMEOUT", "db-query-timeout", "timeout of a single database operation", func(c *Config) interface{} { return &c.Database.QueryTimeout }},
+
+	{"DB_TRANSFER_TIMEOUT", "db-transfer-timeout", "timeout of a whole export or import", func(c *Config) interface{} { return &c.Database.TransferTimeout }},
+
+	{"DB_BATCH_TIMEOUT", "db-batch-timeout", "timeout of writing a batch of pull requests", func(c *Config) interface{} { return &c.Database.BatchTimeout }},
+
+	{"DB_CONNECT_TIMEOUT", "db-connect-timeout", "timeout of opening a connection", func(c *Config) interface{} { return &c.Database.ConnectTimeout }},
+
+	{"MIGRATION_PATH", "migration-path", "directory with goose migrations", func(c *Config) interface{} { return &c.Database.MigrationPath }},
+
+	{"CACHE_CAP", "cache-cap", "entries per cache", func(c *Config) interface{} { return &c.Cache.Cap }},
+
+	{"CACHE_SHARDS", "cache-shards", "independent segments per cache", func(c *Config) interface{} { return &c.Cache.Shards }},
+
+	{"CACHE_TTL", "cache-ttl", "cache entry lifetime, 0 for none", func(c *Config) interface{} { return &c.Cache.TTL }},
+
+	{"CACHE_WARMUP", "cache-warmup", "warm-up policy: all, recent, open, none", func(c *Config) interface{} { return &c.Cache.Warmup }},
+
+	{"CACHE_WARMUP_LIMIT", "cache-warmup-limit", "entries loaded by the recent policy, 0 for cache-cap", func(c *Config) interface{} { return &c.Cache.WarmupLimit }},
+
+	{"ASSIGNMENT_REVIEWERS", "reviewers", "reviewers assigned to a new pull request", func(c *Config) interface{} { return &c.Assignment.Reviewers }},
+
+	{"ASSIGNMENT_STRATEGY", "strategy", "reviewer assignment strategy: ordered, random", func(c *Config) interface{} { return &c.Assignment.Strategy }},
+
+	{"ASSIGNMENT_PAIR_WINDOW", "pair-window", "how far back repeat author-reviewer pairs are counted", func(c *Config) interface{} { return &c.Assignment.PairWindow }},
+
+	{"ASSIGNMENT_PAIR_WEIGHT", "pair-weight", "extra reviews counted per repeat pair, 0 disables", func(c *Config) interface{} { return &c.Assignment.PairWeight }},
+
+	{"TRACE_EXPORTER", "trace-exporter", "trace exporter: none, stdout, otlp", func(c *Config) interface{} { return &c.Trace.Exporter }},
+
+	{"TRACE_SAMPLE_RATIO", "trace-sample-ratio", "share of traced requests from 0 to 1", func(c *Config) interface{} { return &c.Trace.SampleRatio }},
+
+	{"RATE_LIMIT_READ", "rate-limit-read", "GET requests per second per client, 0 disables", func(c *Config) interface{} { return &c.RateLimit.ReadRate }},
+
+	{"RATE_LIMIT_READ_BURST", "rate-limit-read-burst", "GET requests a client may send at once", func(c *Config) interface{} { return &c.RateLimit.ReadBurst }},
+
+	{"RATE_LIMIT_WRITE", "rate-limit-write", "mutating requests per second per client, 0 disables", func(c *Config) interface{} { return &c.RateLimit.WriteRate }},
+
+	{"RATE_LIMIT_WRITE_BURST", "rate-limit-write-burst", "mutating requests a client may send at once", func(c *Config) interface{} { return &c.RateLimit.WriteBurst }},
+
+	{"RATE_LIMIT_TOKENS", "", "", func(c *Config) interface{} { return &c.RateLimit.Tokens }}, // secrets are kept out of flags
+
+	{"RATE_LIMIT_TRUSTED_PROXIES", "trusted-proxies", "comma separated CIDRs of proxies trusted for X-Forwarded-For", func(c *Config) interface{} { return &c.RateLimit.TrustedProxies }},
+
+	{"RATE_LIMIT_MAX_CLIENTS", "rate-limit-max-clients", "clients tracked per budget, new ones beyond it share a bucket", func(c *Config) interface{} { return &c.RateLimit.MaxClients }},
+
+	{"SLA_DEFAULT", "sla-default", "review SLA of teams without a policy", func(c *Config) interface{} { return &c.SLA.Default }},
+
+	{"SLA_CHECK_INTERVAL", "sla-check-interval", "how often overdue reviews are checked, 0 disables reminders", func(c *Config) interface{} { return &c.SLA.CheckInterval }},
+
+	{"SLA_REMIND_EVERY", "sla-remind-every", "pause between reminders about the same review", func(c *Config) interface{} { return &c.SLA.RemindEvery }},
+
+	{"SLA_BATCH_SIZE", "sla-batch-size", "reminders sent per check", func(c *Config) interface{} { return &c.SLA.BatchSize }},
+}
+
+// ValidationError lists every problem found in the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (v *ValidationError) Error() string {
+
+	return "invalid configuration: " + strings.Join(v.Problems, "; ")
+
+}
+
+func (v *ValidationError) add(format string, args ...interface{}) {
+
+	v.Problems = append(v.Problems, fmt.Sprintf(format, args...))
+
+}
+
+// Load builds the configuration from defaults, then the YAML file, then env, then flags in args
+// The file is given by -config or CONFIG_FILE and is optional
+func Load(args []string) (Config, error) {
+
+	cfg := Default()
+
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+
+	fs.SetOutput(io.Discard)
+
+	path := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML config file")
+
+	for _, s := range settings {
+
+		if s.flag != "" {
+
+			fs.String(s.flag, "", s.usage+" (env "+s.env+")")
+
+		}
+
+	}
+
+	if err := fs.Parse(args); err != nil {
+
+		if errors.Is(err, flag.ErrHelp) {
+
+			return Config{}, err
+
+		}
+
+		return Config{}, &ValidationError{Problems: []string{err.Error()}}
+
+	}
+
+	problems := &ValidationError{}
+
+	cfg.File = *path
+
+	if *path != "" {
+
+		if err := loadFile(*path, &cfg); err != nil {
+
+			problems.add("%s: %v", *path, err)
+
+		}
+
+	}
+
+	for _, s := range settings {
+
+		if value, ok := os.LookupEnv(s.env); ok && value != "" {
+
+			if err := setValue(s.field(&cfg), value); err != nil {
+
+				problems.add("%s: %v", s.env, err)
+
+			}
+
+		}
+
+	}
+
+	fs.Visit(func(f *flag.Flag) { // Only flags given on the command line override
+
+		for _, s := range settings {
+
+			if s.flag == f.Name {
+
+				if err := setValue(s.field(&cfg), f.Value.String()); err != nil {
+
+					problems.add("-%s: %v", s.flag, err)
+
+				}
+
+			}
+
+		}
+
+	})
+
+	problems.Problems = append(problems.Problems, cfg.problems()...)
+
+	if len(problems.Problems) != 0 {
+
+		return Config{}, problems
+
+	}
+
+	return cfg, nil
+
+}
+
+// Usage prints the flags accepted by Load
+func Usage(w io.Writer) {
+
+	fmt.Fprintln(w, "  -config string\n    \tYAML config file (env CONFIG_FILE)")
+
+	for _, s := range settings {
+
+		if s.flag != "" {
+
+			fmt.Fprintf(w, "  -%s string\n    \t%s (env %s)\n", s.flag, s.usage, s.env)
+
+		}
+
+	}
+
+}
+
+// loadFile decodes the YAML file over cfg, unknown keys are errors so typos do not pass silently
+func loadFile(path string, cfg *Config) error {
+
+	f, err := os.Open(path)
 
 	if err != nil {
 
-		logger.Fatal(err, "SELECT_TIMEOUT is not number")
+		return err
+
+	}
+
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+
+	dec.KnownFields(true)
+
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) { // An empty file keeps the defaults
+
+		return err
 
 	}
 
-	PostgresTimeOut = time.Duration(PostgresTimeOutSec) * time.Second
+	return nil
+
+}
+
+// setValue parses value into the field, durations also accept plain numbers as seconds
+func setValue(field interface{}, value string) error {
+
+	switch p := field.(type) {
+
+	case *string:
+
+		*p = value
+
+	case *int:
+
+		n, err := strconv.Atoi(value)
+
+		if err != nil {
+
+			return fmt.Errorf("%q is not a number", value)
+
+		}
+
+		*p = n
+
+	case *int32:
+
+		n, err := strconv.ParseInt(value, 10, 32)
+
+		if err != nil {
+
+			return fmt.Errorf("%q is not a number", value)
+
+		}
+
+		*p = int32(n)
+
+	case *int64:
+
+		n, err := strconv.ParseInt(value, 10, 64)
+
+		if err != nil {
+
+			return fmt.Errorf("%q is not a number", value)
+
+		}
+
+		*p = n
+
+	case *float64:
+
+		n, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+
+			return fmt.Errorf("%q is not a number", value)
+
+		}
+
+		*p = n
+
+	case *time.Duration:
+
+		if sec, err := strconv.Atoi(value); err == nil {
+
+			*p = time.Duration(sec) * time.Second
+
+			return nil
+
+		}
+
+		d, err := time.ParseDuration(value)
+
+		if err != nil {
+
+			return fmt.Errorf("%q is not a duration", value)
+
+		}
+
+		*p = d
+
+	}
+
+	return nil
+
+}
+
+// Validate reports every invalid setting at once
+func (c Config) Validate() error {
+
+	if problems := c.problems(); len(problems) != 0 {
+
+		return &ValidationError{Problems: problems}
+
+	}
+
+	return nil
+
+}
+
+func (c Config) problems() []string {
+
+	v := &ValidationError{}
+
+	if c.Server.HTTPAddr == "" {
+
+		v.add("server.http_addr is required")
+
+	}
+
+	if c.Server.GRPCAddr == "" {
+
+		v.add("server.grpc_addr is required")
+
+	}
+
+	if c.Server.HealthTimeout <= 0 {
+
+		v.add("server.health_timeout must be positive")
+
+	}
+
+	if c.Server.ShutdownDrain < 0 {
+
+		v.add("server.shutdown_drain must not be negative")
+
+	}
+
+	if c.Server.TeamBodyLimit < 1 {
+
+		v.add("server.team_body_limit must be positive")
+
+	}
+
+	if c.Server.ImportBodyLimit < 1 {
+
+		v.add("server.import_body_limit must be positive")
+
+	}
+
+	if !oneOf(c.Log.Format, logFormats) {
+
+		v.add("log.format must be one of %s", strings.Join(logFormats, ", "))
+
+	}
+
+	if !oneOf(c.Log.Level, logLevels) {
+
+		v.add("log.level must be one of %s", strings.Join(logLevels, ", "))
+
+	}
+
+	if c.Database.URL == "" {
+
+		v.add("database.url is required (POSTGRES_URL)")
+
+	}
+
+	if c.Database.MaxConns < 1 {
+
+		v.add("database.max_conns must be at least 1")
+
+	}
+
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+
+		v.add("database.min_conns must be between 0 and database.max_conns")
+
+	}
+
+	if c.Database.QueryTimeout <= 0 {
+
+		v.add("database.query_timeout must be positive")
+
+	}
+
+	if c.Database.TransferTimeout <= 0 {
+
+		v.add("database.transfer_timeout must be positive")
+
+	}
+
+	if c.Database.BatchTimeout <= 0 {
+
+		v.add("database.batch_timeout must be positive")
+
+	}
+
+	if c.Database.ConnectTimeout <= 0 {
+
+		v.add("database.connect_timeout must be positive")
+
+	}
+
+	if c.Cache.Cap < 1 {
+
+		v.add("cache.cap must be at least 1")
+
+	}
+
+	if c.Cache.Shards < 1 {
+
+		v.add("cache.shards must be at least 1")
+
+	}
+
+	if c.Cache.TTL < 0 {
+
+		v.add("cache.ttl must not be negative")
+
+	}
+
+	if !oneOf(c.Cache.Warmup, warmups) {
+
+		v.add("cache.warmup must be one of %s", strings.Join(warmups, ", "))
+
+	}
+
+	if c.Cache.WarmupLimit < 0 {
+
+		v.add("cache.warmup_limit must not be negative")
+
+	}
+
+	if c.Assignment.Reviewers < 1 {
+
+		v.add("assignment.reviewers must be at least 1")
+
+	}
+
+	if !oneOf(c.Assignment.Strategy, strategies) {
+
+		v.add("assignment.strategy must be one of %s", strings.Join(strategies, ", "))
+
+	}
+
+	if c.Assignment.PairWindow < 0 {
+
+		v.add("assignment.pair_window must not be negative")
+
+	}
+
+	if c.Assignment.PairWeight < 0 {
+
+		v.add("assignment.pair_weight must not be negative")
+
+	}
+
+	if !oneOf(c.Trace.Exporter, traceExporters) {
+
+		v.add("trace.exporter must be one of %s", strings.Join(traceExporters, ", "))
+
+	}
+
+	if c.Trace.SampleRatio < 0 || c.Trace.SampleRatio > 1 {
+
+		v.add("trace.sample_ratio must be between 0 and 1")
+
+	}
+
+	if c.RateLimit.ReadRate < 0 || c.RateLimit.WriteRate < 0 {
+
+		v.add("rate_limit rates must not be negative")
+
+	}
+
+	if c.RateLimit.ReadRate > 0 && c.RateLimit.ReadBurst < 1 {
+
+		v.add("rate_limit.read_burst must be at least 1 when read_rate is set")
+
+	}
+
+	if c.RateLimit.WriteRate > 0 && c.RateLimit.WriteBurst < 1 {
+
+		v.add("rate_limit.write_burst must be at least 1 when write_rate is set")
+
+	}
+
+	if c.RateLimit.MaxClients < 1 {
+
+		v.add("rate_limit.max_clients must be at least 1")
+
+	}
+
+	for _, cidr := range SplitList(c.RateLimit.TrustedProxies) {
+
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+
+			v.add("rate_limit.trusted_proxies: %q is not a CIDR", cidr)
+
+		}
+
+	}
+
+	if c.SLA.Default <= 0 {
+
+		v.add("sla.default must be positive")
+
+	}
+
+	if c.SLA.CheckInterval < 0 {
+
+		v.add("sla.check_interval must not be negative")
+
+	}
+
+	if c.SLA.RemindEvery <= 0 {
+
+		v.add("sla.remind_every must be positive")
+
+	}
+
+	if c.SLA.BatchSize < 1 {
+
+		v.add("sla.batch_size must be at least 1")
+
+	}
+
+	return v.Problems
+
+}
+
+// RestartRequired lists the sections of next that differ from c in settings that reload cannot apply
+// Reloadable settings are the log level, cache TTL, assignment defaults and rate limits
+func (c Config) RestartRequired(next Config) []string {
+
+	a, b := c.structural(), next.structural()
+
+	var sections []string
+
+	if a.Server != b.Server {
+
+		sections = append(sections, "server")
+
+	}
+
+	if a.Log != b.Log {
+
+		sections = append(sections, "log")
+
+	}
+
+	if a.Database != b.Database {
+
+		sections = append(sections, "database")
+
+	}
+
+	if a.Cache != b.Cache {
+
+		sections = append(sections, "cache")
+
+	}
+
+	if a.Trace != b.Trace {
+
+		sections = append(sections, "trace")
+
+	}
+
+	if a.SLA != b.SLA {
+
+		sections = append(sections, "sla")
+
+	}
+
+	return sections
+
+}
+
+// structural drops the reloadable settings
+func (c Config) structural() Config {
+
+	c.Log.Level = ""
+
+	c.Cache.TTL = 0
+
+	c.Assignment = Assignment{}
+
+	c.RateLimit = RateLimit{}
+
+	return c
+
+}
+
+// SplitList splits a comma separated setting, dropping blanks around and between items
+func SplitList(value string) []string {
+
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+
+		if item = strings.TrimSpace(item); item != "" {
+
+			items = append(items, item)
+
+		}
+
+	}
+
+	return items
+
+}
+
+func oneOf(value string, allowed []string) bool {
+
+	for _, a := range allowed {
+
+		if value == a {
+
+			return true
+
+		}
+
+	}
 
-	MigrationPath = os.Getenv("MIGRATION_PATH")
+	return false
 
 }
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Precedence(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	file := "server:\n  http_addr: \":8000\"\ndatabase:\n  url: postgres://file\n  query_timeout: 10s\ncache:\n  cap: 50\nassignment:\n  reviewers: 3\n"
+
+	require.NoError(t, os.WriteFile(path, []byte(file), 0o600))
+
+	t.Setenv("CONFIG_FILE", path)
+
+	t.Setenv("POSTGRES_URL", "postgres://env")
+
+	t.Setenv("POSTGRES_TIMEOUT", "4") // Plain numbers are seconds
+
+	t.Setenv("CACHE_CAP", "")
+
+	cfg, err := Load([]string{"-reviewers", "1"})
+
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8000", cfg.Server.HTTPAddr) // file
+
+	assert.Equal(t, "postgres://env", cfg.Database.URL) // env over file
+
+	assert.Equal(t, 4*time.Second, cfg.Database.QueryTimeout)
+
+	assert.Equal(t, 50, cfg.Cache.Cap) // empty env keeps the file value
+
+	assert.Equal(t, 1, cfg.Assignment.Reviewers) // flag over file
+
+	assert.Equal(t, ":9090", cfg.Server.GRPCAddr) // default
+
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("POSTGRES_URL", "")
+
+	t.Setenv("CACHE_CAP", "many")
+
+	t.Setenv("LOG_FORMAT", "xml")
+
+	_, err := Load([]string{"-trace-sample-ratio", "2"})
+
+	var failure *ValidationError
+
+	require.ErrorAs(t, err, &failure)
+
+	assert.ElementsMatch(t, []string{
+
+		`CACHE_CAP: "many" is not a number`,
+
+		"log.format must be one of json, console",
+
+		"database.url is required (POSTGRES_URL)",
+
+		"trace.sample_ratio must be between 0 and 1",
+	}, failure.Problems)
+
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("cache:\n  capacity: 10\n"), 0o600))
+
+	t.Setenv("POSTGRES_URL", "postgres://env")
+
+	_, err := Load([]string{"-config", path})
+
+	assert.ErrorContains(t, err, "capacity")
+
+}
+
+func TestRestartRequired(t *testing.T) {
+
+	current := Default()
+
+	next := Default()
+
+	next.Log.Level = "debug"
+
+	next.Cache.TTL = time.Minute
+
+	next.Assignment = Assignment{Reviewers: 3, Strategy: StrategyRandom}
+
+	assert.Empty(t, current.RestartRequired(next)) // Only reloadable settings changed
+
+	next.Cache.Cap = 10
+
+	next.Server.HTTPAddr = ":8081"
+
+	assert.Equal(t, []string{"server", "cache"}, current.RestartRequired(next))
+
+}
@@ -0,0 +1,284 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// LoadTeamsFromDB streams up to limit most recent teams with their members in one query
+// Teams are passed to fn oldest first, so the newest end up most recently used in an LRU
+func LoadTeamsFromDB(ctx context.Context, limit int, fn func(models.Team)) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "LoadTeamsFromDB", "")
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	// Query selected teams joined with their members, grouped by team
+	rows, err := DB.Query(dbCtx, `
+
+        SELECT t.team_name, u.user_id, u.username, u.is_active, u.level, u.skills
+
+        FROM (SELECT team_id, team_name FROM teams ORDER BY team_id DESC LIMIT $1) t
+
+        JOIN users u ON u.team_id = t.team_id
+
+        ORDER BY t.team_id, u.user_id`, limit)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "LoadTeamsFromDB", "")
+
+		return err
+
+	}
+
+	defer rows.Close()
+
+	var team models.Team
+
+	// Rows arrive grouped by team, emit a team when the next one starts
+	for rows.Next() {
+
+		var teamName string
+
+		var member models.TeamMember
+
+		err := rows.Scan(&teamName, &member.UserID, &member.Username, &member.IsActive, &member.Level, &member.Skills)
+
+		if err != nil {
+
+			logQueryError(ctx, err, "LoadTeamsFromDB", "")
+
+			return err
+
+		}
+
+		if teamName != team.TeamName && len(team.Members) != 0 {
+
+			fn(team)
+
+			team = models.Team{}
+
+		}
+
+		team.TeamName = teamName
+
+		team.Members = append(team.Members, member)
+
+	}
+
+	if err := rows.Err(); err != nil {
+
+		logQueryError(ctx, err, "LoadTeamsFromDB", "")
+
+		return err
+
+	}
+
+	if len(team.Members) != 0 {
+
+		fn(team)
+
+	}
+
+	return nil
+
+}
+
+// LoadUsersFromDB streams up to limit users with their team names in one query
+func LoadUsersFromDB(ctx context.Context, limit int, fn func(models.User)) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "LoadUsersFromDB", "")
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	// Query users of the most recent teams first, then reverse to oldest first
+	rows, err := DB.Query(dbCtx, `
+
+        SELECT user_id, username, is_active, team_name, level, skills FROM (
+
+            SELECT u.user_id, u.username, u.is_active, t.team_name, t.team_id, u.level, u.skills
+
+            FROM users u
+
+            JOIN teams t ON u.team_id = t.team_id
+
+            ORDER BY t.team_id DESC, u.user_id DESC
+
+            LIMIT $1
+
+        ) recent
+
+        ORDER BY team_id, user_id`, limit)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "LoadUsersFromDB", "")
+
+		return err
+
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var user models.User
+
+		err := rows.Scan(&user.UserID, &user.Username, &user.IsActive, &user.TeamName, &user.Level, &user.Skills)
+
+		if err != nil {
+
+			logQueryError(ctx, err, "LoadUsersFromDB", "")
+
+			return err
+
+		}
+
+		fn(user)
+
+	}
+
+	if err := rows.Err(); err != nil {
+
+		logQueryError(ctx, err, "LoadUsersFromDB", "")
+
+		return err
+
+	}
+
+	return nil
+
+}
+
+// LoadPRsFromDB streams up to limit most recent pull requests with their reviewers in one query
+// If status is not empty only pull requests in that status are loaded
+func LoadPRsFromDB(ctx context.Context, limit int, status string, fn func(models.PullRequest)) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "LoadPRsFromDB", "")
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	// Query the newest matching PRs with aggregated reviewers, then reverse to oldest first
+	rows, err := DB.Query(dbCtx, `
+
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
+
+               COALESCE(array_agg(r.user_id ORDER BY r.slot) FILTER (WHERE r.user_id IS NOT NULL), '{}'),
+
+               pr.created_at, pr.merged_at, pr.priority, pr.labels
+
+        FROM (
+
+            SELECT * FROM pull_requests
+
+            WHERE $2 = '' OR status = $2
+
+            ORDER BY created_at DESC NULLS LAST, pull_request_id DESC
+
+            LIMIT $1
+
+        ) pr
+
+        LEFT JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id AND r.state = $3
+
+        GROUP BY pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.priority, pr.labels
+
+        ORDER BY pr.created_at NULLS FIRST, pr.pull_request_id`, limit, status, ReviewerAssigned)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "LoadPRsFromDB", "")
+
+		return err
+
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var pr models.PullRequest
+
+		var createdAt, mergedAt sql.NullTime
+
+		err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status,
+
+			&pr.AssignedReviewers, &createdAt, &mergedAt, &pr.Priority, &pr.Labels)
+
+		if err != nil {
+
+			logQueryError(ctx, err, "LoadPRsFromDB", "")
+
+			return err
+
+		}
+
+		// Convert nullable timestamps to string format
+		if createdAt.Valid {
+
+			pr.CreatedAt = createdAt.Time.Format(time.RFC3339)
+
+		}
+
+		if mergedAt.Valid {
+
+			pr.MergedAt = mergedAt.Time.Format(time.RFC3339)
+
+		}
+
+		fn(pr)
+
+	}
+
+	if err := rows.Err(); err != nil {
+
+		logQueryError(ctx, err, "LoadPRsFromDB", "")
+
+		return err
+
+	}
+
+	return nil
+
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/exaring/otelpgx"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose"
@@ -14,14 +15,34 @@ import (
 
 var (
 	DB *pgxpool.Pool
+
+	dbConfig config.Database // query timeout and migration path used by the functions of this package
 )
 
 // InitDB initializes the database connection pool
-func InitDB(ctx context.Context, dsn string) {
+func InitDB(ctx context.Context, cfg config.Database) {
 
 	var err error
 
-	DB, err = pgxpool.New(ctx, dsn) // Create connection pool
+	dbConfig = cfg
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
+
+	if err != nil {
+
+		logger.Fatal(err, "invalid database URL")
+
+	}
+
+	poolConfig.MaxConns = cfg.MaxConns
+
+	poolConfig.MinConns = cfg.MinConns
+
+	poolConfig.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+
+	poolConfig.ConnConfig.Tracer = otelpgx.NewTracer() // Span for every query, child of the span in ctx
+
+	DB, err = pgxpool.NewWithConfig(ctx, poolConfig) // Create connection pool
 
 	if err != nil {
 
@@ -38,22 +59,36 @@ func InitDB(ctx context.Context, dsn string) {
 }
 
 // run goose migrations
-func RunMigrations(dsn string) {
+func RunMigrations(cfg config.Database) {
 
-	DB, err := sql.Open("postgres", dsn) // Open database connection for migrations
+	if err := Migrate(cfg, "up"); err != nil { // Run all up migrations
+
+		logger.Fatal(err, "failed to run migrations")
+
+	}
+
+}
+
+// Migrate runs a goose command (up, down, status, ...) against the migrations directory
+func Migrate(cfg config.Database, command string) error {
+
+	DB, err := sql.Open("postgres", cfg.URL) // Open database connection for migrations
 
 	if err != nil {
 
-		logger.Fatal(err, "failed to open db for migrations")
+		return err
 
 	}
 
 	defer DB.Close()
 
-	if err := goose.Up(DB, config.MigrationPath); err != nil { // Run all up migrations
+	return goose.Run(command, DB, cfg.MigrationPath)
 
-		logger.Fatal(err, "failed to run migrations")
+}
 
-	}
+// logQueryError logs a failed query with its name and the ID of the entity it touched, empty for bulk queries
+func logQueryError(ctx context.Context, err error, query string, entityID string) {
+
+	logger.ErrorCtx(ctx, err, "database query failed", "query", query, "entity_id", entityID)
 
 }
@@ -0,0 +1,630 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Import conflict policies for rows that already exist
+const (
+	ConflictSkip = "skip" // keep the existing row
+
+	ConflictOverwrite = "overwrite" // replace the existing row with the imported one
+
+	ConflictFail = "fail" // abort the whole import
+)
+
+// ExportFromDB reads all teams, users, team policies and pull requests with their reviewers and history from one consistent snapshot
+// Records are passed to emit in dump order as rows arrive, header first, so the dump is never held in memory
+func ExportFromDB(ctx context.Context, emit func(models.DumpRecord) error) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "ExportFromDB", "")
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.TransferTimeout) // The whole dump, not a single query
+
+	defer cancel()
+
+	// Read everything in one repeatable read transaction so references in the dump are consistent
+	tx, err := DB.BeginTx(dbCtx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ExportFromDB", "")
+
+		return err
+
+	}
+
+	defer tx.Rollback(dbCtx)
+
+	err = emit(models.DumpRecord{Type: models.RecordHeader, Version: models.DumpVersion, ExportedAt: time.Now().UTC().Format(time.RFC3339)})
+
+	if err != nil {
+
+		return err
+
+	}
+
+	err = exportRows(dbCtx, tx, emit, `SELECT team_name FROM teams ORDER BY team_id`, func(rows pgx.Rows) (models.DumpRecord, error) {
+
+		var team models.DumpTeam
+
+		err := rows.Scan(&team.TeamName)
+
+		return models.DumpRecord{Type: models.RecordTeam, Team: &team}, err
+
+	})
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ExportFromDB", "")
+
+		return err
+
+	}
+
+	err = exportRows(dbCtx, tx, emit, `
+
+        SELECT u.user_id, u.username, COALESCE(t.team_name, ''), u.is_active, u.level, u.skills
+
+        FROM users u
+
+        LEFT JOIN teams t ON u.team_id = t.team_id
+
+        ORDER BY u.user_id`, func(rows pgx.Rows) (models.DumpRecord, error) {
+
+		var user models.User
+
+		err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.Level, &user.Skills)
+
+		return models.DumpRecord{Type: models.RecordUser, User: &user}, err
+
+	})
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ExportFromDB", "")
+
+		return err
+
+	}
+
+	err = exportRows(dbCtx, tx, emit, `
+
+        SELECT team_name, review_sla_seconds, escalate_after_seconds, max_escalations, min_senior_reviewers, skill_labels
+
+        FROM team_policies
+
+        ORDER BY team_name`, func(rows pgx.Rows) (models.DumpRecord, error) {
+
+		var policy models.TeamPolicy
+
+		var slaSeconds, escalateSeconds int64
+
+		err := rows.Scan(&policy.TeamName, &slaSeconds, &escalateSeconds, &policy.MaxEscalations, &policy.MinSeniorReviewers, &policy.SkillLabels)
+
+		policy.ReviewSLAHours, policy.EscalateAfterHours = int(slaSeconds/3600), int(escalateSeconds/3600)
+
+		return models.DumpRecord{Type: models.RecordTeamPolicy, TeamPolicy: &policy}, err
+
+	})
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ExportFromDB", "")
+
+		return err
+
+	}
+
+	// Each pull request row carries its full reviewer history, including unassigned reviewers, and its reviewer changes
+	err = exportRows(dbCtx, tx, emit, `
+
+        SELECT p.pull_request_id, p.pull_request_name, COALESCE(p.author_id, ''), p.status, p.created_at, p.merged_at, p.priority, p.labels,
+
+            COALESCE((SELECT json_agg(json_build_object('user_id', r.user_id, 'slot', r.slot, 'state', r.state,
+
+                    'assigned_at', r.assigned_at, 'assigned_by', r.assigned_by) ORDER BY r.state, r.slot)
+
+                FROM pr_reviewers r WHERE r.pull_request_id = p.pull_request_id), '[]'),
+
+            COALESCE((SELECT json_agg(json_build_object('action', h.action, 'user_id', h.user_id, 'replaced_by', h.replaced_by,
+
+                    'reason', h.reason, 'at', h.created_at) ORDER BY h.id)
+
+                FROM pr_history h WHERE h.pull_request_id = p.pull_request_id), '[]')
+
+        FROM pull_requests p
+
+        ORDER BY p.created_at NULLS FIRST, p.pull_request_id`, func(rows pgx.Rows) (models.DumpRecord, error) {
+
+		var pr models.DumpPullRequest
+
+		var createdAt, mergedAt sql.NullTime
+
+		var reviewers []exportedReviewer
+
+		var history []exportedHistoryEntry
+
+		err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt, &pr.Priority, &pr.Labels, &reviewers, &history)
+
+		if err != nil {
+
+			return models.DumpRecord{}, err
+
+		}
+
+		// Convert nullable timestamps to string format
+		if createdAt.Valid {
+
+			pr.CreatedAt = createdAt.Time.Format(time.RFC3339)
+
+		}
+
+		if mergedAt.Valid {
+
+			pr.MergedAt = mergedAt.Time.Format(time.RFC3339)
+
+		}
+
+		pr.Reviewers = make([]models.DumpReviewer, 0, len(reviewers))
+
+		for _, reviewer := range reviewers {
+
+			pr.Reviewers = append(pr.Reviewers, models.DumpReviewer{UserID: reviewer.UserID, Slot: reviewer.Slot, State: reviewer.State,
+
+				AssignedAt: reviewer.AssignedAt.Format(time.RFC3339), AssignedBy: reviewer.AssignedBy})
+
+		}
+
+		for _, entry := range history {
+
+			pr.History = append(pr.History, models.PRHistoryEntry{Action: entry.Action, UserID: entry.UserID, ReplacedBy: entry.ReplacedBy,
+
+				Reason: entry.Reason, At: entry.At.Format(time.RFC3339)})
+
+		}
+
+		return models.DumpRecord{Type: models.RecordPullRequest, PullRequest: &pr}, nil
+
+	})
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ExportFromDB", "")
+
+		return err
+
+	}
+
+	return nil
+
+}
+
+// exportedReviewer and exportedHistoryEntry are the JSON rows aggregated per pull request by ExportFromDB
+type exportedReviewer struct {
+	UserID string `json:"user_id"`
+
+	Slot int `json:"slot"`
+
+	State string `json:"state"`
+
+	AssignedAt time.Time `json:"assigned_at"`
+
+	AssignedBy string `json:"assigned_by"`
+}
+
+type exportedHistoryEntry struct {
+	Action string `json:"action"`
+
+	UserID string `json:"user_id"`
+
+	ReplacedBy string `json:"replaced_by"`
+
+	Reason string `json:"reason"`
+
+	At time.Time `json:"at"`
+}
+
+// exportRows runs query and passes a record built from every row to emit as soon as it is read
+func exportRows(ctx context.Context, tx pgx.Tx, emit func(models.DumpRecord) error, query string,
+	record func(pgx.Rows) (models.DumpRecord, error)) error {
+
+	rows, err := tx.Query(ctx, query)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		rec, err := record(rows)
+
+		if err != nil {
+
+			return err
+
+		}
+
+		if err := emit(rec); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return rows.Err()
+
+}
+
+// ImportToDB loads a dump in a single transaction, rows that already exist are handled by policy
+// With ConflictFail the first existing row aborts the import with errs.ErrImportConflict
+func ImportToDB(ctx context.Context, dump models.Dump, policy string) (models.ImportResult, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "ImportToDB", "")
+
+		return models.ImportResult{}, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.TransferTimeout) // The whole import, not a single query
+
+	defer cancel()
+
+	tx, err := DB.Begin(dbCtx) // Begin transaction, a failed import leaves the database untouched
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ImportToDB", "")
+
+		return models.ImportResult{}, err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	res := models.ImportResult{Policy: policy}
+
+	// Teams have no attributes besides the name, so existing teams are never updated
+	batch := &pgx.Batch{}
+
+	for _, team := range dump.Teams {
+
+		batch.Queue(`INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT (team_name) DO NOTHING RETURNING true`, team.TeamName)
+
+	}
+
+	_, err = importBatch(dbCtx, tx, batch, policy, &res.Teams, func(i int) string { return "team " + dump.Teams[i].TeamName })
+
+	if err != nil {
+
+		return models.ImportResult{}, err
+
+	}
+
+	batch = &pgx.Batch{}
+
+	for _, user := range dump.Users {
+
+		batch.Queue(`
+
+            INSERT INTO users (user_id, username, team_id, is_active, level, skills)
+
+            VALUES ($1, $2, (SELECT team_id FROM teams WHERE team_name = $3), $4, COALESCE(NULLIF($5, ''), 'middle'), $6)
+
+            ON CONFLICT (user_id) `+onConflict(policy, `
+
+                username = EXCLUDED.username,
+
+                team_id = EXCLUDED.team_id,
+
+                is_active = EXCLUDED.is_active,
+
+                level = EXCLUDED.level,
+
+                skills = EXCLUDED.skills`)+`
+
+            RETURNING xmax = 0`, user.UserID, user.Username, user.TeamName, user.IsActive, user.Level, emptyIfNil(user.Skills))
+
+	}
+
+	_, err = importBatch(dbCtx, tx, batch, policy, &res.Users, func(i int) string { return "user " + dump.Users[i].UserID })
+
+	if err != nil {
+
+		return models.ImportResult{}, err
+
+	}
+
+	batch = &pgx.Batch{}
+
+	for _, teamPolicy := range dump.TeamPolicies {
+
+		batch.Queue(`
+
+            INSERT INTO team_policies (team_name, review_sla_seconds, escalate_after_seconds, max_escalations, min_senior_reviewers, skill_labels)
+
+            VALUES ($1, $2, $3, $4, $5, $6)
+
+            ON CONFLICT (team_name) `+onConflict(policy, `
+
+                review_sla_seconds = EXCLUDED.review_sla_seconds,
+
+                escalate_after_seconds = EXCLUDED.escalate_after_seconds,
+
+                max_escalations = EXCLUDED.max_escalations,
+
+                min_senior_reviewers = EXCLUDED.min_senior_reviewers,
+
+                skill_labels = EXCLUDED.skill_labels`)+`
+
+            RETURNING xmax = 0`, teamPolicy.TeamName, int64(teamPolicy.ReviewSLAHours)*3600, int64(teamPolicy.EscalateAfterHours)*3600,
+
+			teamPolicy.MaxEscalations, teamPolicy.MinSeniorReviewers, emptyIfNil(teamPolicy.SkillLabels))
+
+	}
+
+	_, err = importBatch(dbCtx, tx, batch, policy, &res.TeamPolicies, func(i int) string { return "team policy " + dump.TeamPolicies[i].TeamName })
+
+	if err != nil {
+
+		return models.ImportResult{}, err
+
+	}
+
+	batch = &pgx.Batch{}
+
+	for _, pr := range dump.PullRequests {
+
+		createdAt, err := nullableTime(pr.CreatedAt)
+
+		if err != nil {
+
+			return models.ImportResult{}, err
+
+		}
+
+		mergedAt, err := nullableTime(pr.MergedAt)
+
+		if err != nil {
+
+			return models.ImportResult{}, err
+
+		}
+
+		batch.Queue(`
+
+            INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, priority, labels)
+
+            VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'normal'), $8)
+
+            ON CONFLICT (pull_request_id) `+onConflict(policy, `
+
+                pull_request_name = EXCLUDED.pull_request_name,
+
+                author_id = EXCLUDED.author_id,
+
+                status = EXCLUDED.status,
+
+                created_at = EXCLUDED.created_at,
+
+                merged_at = EXCLUDED.merged_at,
+
+                priority = EXCLUDED.priority,
+
+                labels = EXCLUDED.labels`)+`
+
+            RETURNING xmax = 0`, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, createdAt, mergedAt, pr.Priority, emptyIfNil(pr.Labels))
+
+	}
+
+	// Reviewers and history are replaced only for pull requests that were written
+	written, err := importBatch(dbCtx, tx, batch, policy, &res.PullRequests, func(i int) string { return "pull request " + dump.PullRequests[i].PullRequestID })
+
+	if err != nil {
+
+		return models.ImportResult{}, err
+
+	}
+
+	batch = &pgx.Batch{}
+
+	for i, pr := range dump.PullRequests {
+
+		if !written[i] {
+
+			continue
+
+		}
+
+		batch.Queue(`DELETE FROM pr_reviewers WHERE pull_request_id = $1`, pr.PullRequestID)
+
+		for _, reviewer := range pr.Reviewers {
+
+			assignedAt, err := time.Parse(time.RFC3339, reviewer.AssignedAt)
+
+			if err != nil {
+
+				return models.ImportResult{}, err
+
+			}
+
+			batch.Queue(`
+
+                INSERT INTO pr_reviewers (pull_request_id, user_id, slot, state, assigned_at, assigned_by)
+
+                VALUES ($1, $2, $3, $4, $5, $6)`,
+
+				pr.PullRequestID, reviewer.UserID, reviewer.Slot, reviewer.State, assignedAt, reviewer.AssignedBy)
+
+		}
+
+		batch.Queue(`DELETE FROM pr_history WHERE pull_request_id = $1`, pr.PullRequestID)
+
+		for _, entry := range pr.History {
+
+			at, err := time.Parse(time.RFC3339, entry.At)
+
+			if err != nil {
+
+				return models.ImportResult{}, err
+
+			}
+
+			batch.Queue(`
+
+                INSERT INTO pr_history (pull_request_id, action, user_id, replaced_by, reason, created_at)
+
+                VALUES ($1, $2, $3, $4, $5, $6)`,
+
+				pr.PullRequestID, entry.Action, entry.UserID, entry.ReplacedBy, entry.Reason, at)
+
+		}
+
+	}
+
+	err = tx.SendBatch(dbCtx, batch).Close() // Close reports the first failed statement
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ImportToDB", "")
+
+		return models.ImportResult{}, err
+
+	}
+
+	err = notifyInvalidation(dbCtx, tx, InvalidateAll, "") // Imported rows may be cached anywhere
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ImportToDB", "")
+
+		return models.ImportResult{}, err
+
+	}
+
+	err = tx.Commit(dbCtx)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "ImportToDB", "")
+
+		return models.ImportResult{}, err
+
+	}
+
+	return res, nil
+
+}
+
+// onConflict builds the ON CONFLICT action for a policy, skip and fail insert only new rows
+func onConflict(policy string, set string) string {
+
+	if policy == ConflictOverwrite {
+
+		return "DO UPDATE SET " + set
+
+	}
+
+	return "DO NOTHING"
+
+}
+
+// importBatch runs upserts returning whether a row was inserted, counts the outcome and reports written rows
+// No returned row means the row existed and was left as is, name describes a row in conflict errors
+func importBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch, policy string,
+	counts *models.ImportCounts, name func(i int) string) ([]bool, error) {
+
+	written := make([]bool, batch.Len())
+
+	results := tx.SendBatch(ctx, batch)
+
+	defer results.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+
+		var inserted bool
+
+		err := results.QueryRow().Scan(&inserted)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+
+			if policy == ConflictFail {
+
+				return nil, fmt.Errorf("%w: %s", errs.ErrImportConflict, name(i))
+
+			}
+
+			counts.Skipped++
+
+			continue
+
+		}
+
+		if err != nil {
+
+			logQueryError(ctx, err, "importBatch", name(i))
+
+			return nil, err
+
+		}
+
+		written[i] = true
+
+		if inserted {
+
+			counts.Inserted++
+
+		} else {
+
+			counts.Updated++
+
+		}
+
+	}
+
+	return written, results.Close()
+
+}
+
+// nullableTime parses an optional RFC3339 timestamp, empty strings become NULL
+func nullableTime(value string) (interface{}, error) {
+
+	if value == "" {
+
+		return nil, nil
+
+	}
+
+	return time.Parse(time.RFC3339, value)
+
+}
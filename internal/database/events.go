@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+)
+
+// EventsChannel is the Postgres NOTIFY channel carrying review events to every instance
+const EventsChannel = "review_events"
+
+// PublishEvent sends a review event to every instance listening with ListenEvents, this one included
+// It is an events.Relay, Postgres limits a payload to 8000 bytes
+func PublishEvent(event events.Event) error {
+
+	if DB == nil { // Check if database connection is initialized
+
+		return fmt.Errorf("database not initialized")
+
+	}
+
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.Background(), dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	_, err = DB.Exec(dbCtx, `SELECT pg_notify($1, $2)`, EventsChannel, string(payload))
+
+	return err
+
+}
+
+// ListenEvents passes review events published by any instance to onEvent until ctx is done
+// onSubscribe runs every time LISTEN succeeds and onDisconnect every time the connection is lost,
+// events published while disconnected are not delivered
+func ListenEvents(ctx context.Context, onEvent func(events.Event), onSubscribe func(), onDisconnect func()) {
+
+	listenLoop(ctx, EventsChannel, onSubscribe, onDisconnect, func(payload string) {
+
+		var event events.Event
+
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+
+			logger.Error(err, "malformed review event payload")
+
+			return
+
+		}
+
+		onEvent(event)
+
+	})
+
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pressly/goose"
+)
+
+// PingDB checks that a pooled connection to Postgres answers within timeout
+func PingDB(ctx context.Context, timeout time.Duration) error {
+
+	if DB == nil { // Check if database connection is initialized
+
+		return fmt.Errorf("database not initialized")
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, timeout)
+
+	defer cancel()
+
+	return DB.Ping(dbCtx)
+
+}
+
+// MigrationVersions returns the applied schema version and the latest version in the migrations directory
+func MigrationVersions(ctx context.Context, timeout time.Duration) (int64, int64, error) {
+
+	if DB == nil { // Check if database connection is initialized
+
+		return 0, 0, fmt.Errorf("database not initialized")
+
+	}
+
+	migrations, err := goose.CollectMigrations(dbConfig.MigrationPath, 0, math.MaxInt64)
+
+	if err != nil {
+
+		return 0, 0, err
+
+	}
+
+	latest, err := migrations.Last()
+
+	if err != nil {
+
+		return 0, 0, errors.New("no migrations found in " + dbConfig.MigrationPath)
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, timeout)
+
+	defer cancel()
+
+	var current int64
+
+	// goose keeps one row per apply or rollback, the latest row of a version tells whether it is applied
+	err = DB.QueryRow(dbCtx, fmt.Sprintf(`
+
+        SELECT COALESCE(MAX(version_id), 0)
+
+        FROM (
+
+            SELECT DISTINCT ON (version_id) version_id, is_applied
+
+            FROM %s
+
+            ORDER BY version_id, id DESC
+
+        ) v
+
+        WHERE is_applied`, goose.TableName())).Scan(&current)
+
+	if err != nil {
+
+		return 0, 0, err
+
+	}
+
+	return current, latest.Version, nil
+
+}
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// SetPRWithHistoryToDB writes a pull request and appends entry to its history in one transaction
+func SetPRWithHistoryToDB(ctx context.Context, pr models.PullRequest, entry models.PRHistoryEntry) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "SetPRWithHistoryToDB", pr.PullRequestID)
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	tx, err := DB.Begin(dbCtx)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetPRWithHistoryToDB", pr.PullRequestID)
+
+		return err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	err = writePR(dbCtx, tx, pr)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetPRWithHistoryToDB", pr.PullRequestID)
+
+		return err
+
+	}
+
+	_, err = tx.Exec(dbCtx, `
+
+        INSERT INTO pr_history (pull_request_id, action, user_id, replaced_by, reason)
+
+        VALUES ($1, $2, $3, $4, $5)`,
+
+		pr.PullRequestID, entry.Action, entry.UserID, entry.ReplacedBy, entry.Reason)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetPRWithHistoryToDB", pr.PullRequestID)
+
+		return err
+
+	}
+
+	return tx.Commit(dbCtx)
+
+}
+
+// GetPRHistoryFromDB returns the recorded reviewer changes of a pull request, oldest first
+func GetPRHistoryFromDB(ctx context.Context, prID string) ([]models.PRHistoryEntry, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "GetPRHistoryFromDB", prID)
+
+		return nil, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	rows, err := DB.Query(dbCtx, `
+
+        SELECT action, user_id, replaced_by, reason, created_at
+
+        FROM pr_history
+
+        WHERE pull_request_id = $1
+
+        ORDER BY id`, prID)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "GetPRHistoryFromDB", prID)
+
+		return nil, err
+
+	}
+
+	defer rows.Close()
+
+	history := []models.PRHistoryEntry{}
+
+	for rows.Next() {
+
+		var entry models.PRHistoryEntry
+
+		var at time.Time
+
+		if err := rows.Scan(&entry.Action, &entry.UserID, &entry.ReplacedBy, &entry.Reason, &at); err != nil {
+
+			logQueryError(ctx, err, "GetPRHistoryFromDB", prID)
+
+			return nil, err
+
+		}
+
+		entry.At = at.Format(time.RFC3339)
+
+		history = append(history, entry)
+
+	}
+
+	if err := rows.Err(); err != nil {
+
+		logQueryError(ctx, err, "GetPRHistoryFromDB", prID)
+
+		return nil, err
+
+	}
+
+	return history, nil
+
+}
+
+// RecentPairsFromDB counts for every reviewer how often they were assigned to pull requests of author within window
+// Reviewers later unassigned are counted too, they were paired with the author all the same
+func RecentPairsFromDB(ctx context.Context, authorID string, window time.Duration) (map[string]int, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "RecentPairsFromDB", authorID)
+
+		return nil, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	rows, err := DB.Query(dbCtx, `
+
+        SELECT r.user_id, COUNT(*)
+
+        FROM pr_reviewers r
+
+        JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+
+        WHERE pr.author_id = $1 AND r.assigned_at >= NOW() - make_interval(secs => $2)
+
+        GROUP BY r.user_id`, authorID, window.Seconds())
+
+	if err != nil {
+
+		logQueryError(ctx, err, "RecentPairsFromDB", authorID)
+
+		return nil, err
+
+	}
+
+	defer rows.Close()
+
+	pairs := make(map[string]int)
+
+	for rows.Next() {
+
+		var userID string
+
+		var count int
+
+		if err := rows.Scan(&userID, &count); err != nil {
+
+			logQueryError(ctx, err, "RecentPairsFromDB", authorID)
+
+			return nil, err
+
+		}
+
+		pairs[userID] = count
+
+	}
+
+	return pairs, rows.Err()
+
+}
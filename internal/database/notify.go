@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+)
+
+// InvalidationChannel is the Postgres NOTIFY channel used to evict cache entries on other instances
+const InvalidationChannel = "cache_invalidation"
+
+// Kinds of cached entities carried in invalidation messages
+const (
+	InvalidateTeam = "team"
+
+	InvalidateUser = "user"
+
+	InvalidatePR = "pr"
+
+	InvalidateAll = "all" // bulk writes such as imports, the key is empty
+)
+
+// InstanceID identifies this process as the origin of the notifications it sends
+var InstanceID = newInstanceID()
+
+// Invalidation is the payload of a cache invalidation notification
+type Invalidation struct {
+	Origin string `json:"origin"`
+
+	Kind string `json:"kind"`
+
+	Key string `json:"key"`
+}
+
+func newInstanceID() string {
+
+	host, _ := os.Hostname()
+
+	suffix := make([]byte, 4)
+
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+
+}
+
+// notifyInvalidation queues a cache invalidation inside tx, Postgres delivers it only on commit
+func notifyInvalidation(ctx context.Context, tx pgx.Tx, kind string, key string) error {
+
+	payload, err := json.Marshal(Invalidation{Origin: InstanceID, Kind: kind, Key: key})
+
+	if err != nil {
+
+		return err
+
+	}
+
+	_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, InvalidationChannel, string(payload))
+
+	return err
+
+}
+
+// ListenInvalidations consumes cache invalidations from other instances until ctx is done
+// onMessage is called for every foreign invalidation, onSubscribe every time LISTEN succeeds,
+// including the first time, since notifications sent before it are lost
+func ListenInvalidations(ctx context.Context, onMessage func(Invalidation), onSubscribe func()) {
+
+	listenLoop(ctx, InvalidationChannel, onSubscribe, func() {}, func(payload string) {
+
+		if msg, ok := DecodeInvalidation(payload); ok {
+
+			onMessage(msg)
+
+		}
+
+	})
+
+}
+
+// listenLoop keeps a LISTEN on channel until ctx is done, reconnecting with a growing backoff
+// onSubscribe runs every time LISTEN succeeds and onDisconnect every time the connection is lost
+func listenLoop(ctx context.Context, channel string, onSubscribe func(), onDisconnect func(), onPayload func(string)) {
+
+	backoff := time.Second
+
+	for ctx.Err() == nil {
+
+		err := listen(ctx, channel, func() {
+
+			onSubscribe()
+
+			backoff = time.Second
+
+		}, onPayload)
+
+		onDisconnect()
+
+		if ctx.Err() != nil {
+
+			return
+
+		}
+
+		logger.Error(err, "notification listener disconnected", "channel", channel)
+
+		select {
+
+		case <-ctx.Done():
+
+			return
+
+		case <-time.After(backoff):
+
+		}
+
+		if backoff < 30*time.Second {
+
+			backoff *= 2
+
+		}
+
+	}
+
+}
+
+// listen holds a dedicated connection with LISTEN on channel and dispatches notification payloads
+func listen(ctx context.Context, channel string, onConnect func(), onPayload func(string)) error {
+
+	if DB == nil { // Check if database connection is initialized
+
+		return fmt.Errorf("database not initialized")
+
+	}
+
+	poolConn, err := DB.Acquire(ctx)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	conn := poolConn.Hijack() // Take connection out of the pool so LISTEN state does not leak
+
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+
+	if err != nil {
+
+		return err
+
+	}
+
+	onConnect()
+
+	for {
+
+		notification, err := conn.WaitForNotification(ctx)
+
+		if err != nil {
+
+			return err
+
+		}
+
+		onPayload(notification.Payload)
+
+	}
+
+}
+
+// DecodeInvalidation parses a notification payload, ok is false for malformed payloads
+// Writes of this instance are decoded too, a read racing the write may have cached the old value after it evicted
+func DecodeInvalidation(payload string) (Invalidation, bool) {
+
+	var msg Invalidation
+
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+
+		logger.Error(err, "malformed cache invalidation payload")
+
+		return Invalidation{}, false
+
+	}
+
+	return msg, true
+
+}
@@ -9,15 +9,24 @@ import (
 
 	"github.com/jackc/pgx/v5"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 )
 
+// Reviewer assignment states stored in pr_reviewers
+const (
+	ReviewerAssigned = "ASSIGNED"
+
+	ReviewerUnassigned = "UNASSIGNED"
+)
+
+// AssignedBySystem marks reviewers assigned by the service itself
+const AssignedBySystem = "system"
+
 // GetPRFromDB retrieves a pull request from the database by its ID
 func GetPRFromDB(ctx context.Context, prID string) (models.PullRequest, error, bool) {
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
 
 	defer cancel()
 
@@ -27,7 +36,7 @@ func GetPRFromDB(ctx context.Context, prID string) (models.PullRequest, error, b
 
 		err = fmt.Errorf("database not initialized")
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetPRFromDB", prID)
 
 		return models.PullRequest{}, err, false
 
@@ -37,20 +46,26 @@ func GetPRFromDB(ctx context.Context, prID string) (models.PullRequest, error, b
 
 	var createdAt, mergedAt sql.NullTime
 
-	// Query pull request from database
+	// Query pull request with its currently assigned reviewers ordered by slot
 	err = DB.QueryRow(dbCtx, `
 
-        SELECT pull_request_id, pull_request_name, author_id, status, 
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
+
+               COALESCE(array_agg(r.user_id ORDER BY r.slot) FILTER (WHERE r.user_id IS NOT NULL), '{}'),
+
+               pr.created_at, pr.merged_at, pr.priority, pr.labels
+
+        FROM pull_requests pr
 
-               assigned_reviewers, created_at, merged_at
+        LEFT JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id AND r.state = $2
 
-        FROM pull_requests 
+        WHERE pr.pull_request_id = $1
 
-        WHERE pull_request_id = $1`, prID).Scan(
+        GROUP BY pr.pull_request_id`, prID, ReviewerAssigned).Scan(
 
 		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status,
 
-		&pr.AssignedReviewers, &createdAt, &mergedAt)
+		&pr.AssignedReviewers, &createdAt, &mergedAt, &pr.Priority, &pr.Labels)
 
 	if err != nil {
 
@@ -60,7 +75,7 @@ func GetPRFromDB(ctx context.Context, prID string) (models.PullRequest, error, b
 
 		}
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetPRFromDB", prID)
 
 		return models.PullRequest{}, err, false
 
@@ -92,66 +107,272 @@ func SetPRToDB(ctx context.Context, pr models.PullRequest) error {
 
 		err = fmt.Errorf("database not initialized")
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "SetPRToDB", pr.PullRequestID)
 
 		return err
 
 	}
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
 
 	defer cancel()
 
-	var createdAt, mergedAt interface{} // Prepare timestamp fields for database
+	tx, err := DB.Begin(dbCtx) // Begin transaction to keep PR and reviewers consistent
 
-	if pr.CreatedAt != "" {
+	if err != nil {
 
-		t, err := time.Parse(time.RFC3339, pr.CreatedAt)
+		logQueryError(ctx, err, "SetPRToDB", pr.PullRequestID)
+
+		return err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	err = writePR(dbCtx, tx, pr)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetPRToDB", pr.PullRequestID)
+
+		return err
+
+	}
+
+	// Commit transaction
+	return tx.Commit(dbCtx)
+
+}
+
+// writeTimeout bounds writing n pull requests, a batch gets BatchTimeout instead of the single query timeout
+func writeTimeout(n int) time.Duration {
+
+	if n > 1 {
+
+		return dbConfig.BatchTimeout
+
+	}
+
+	return dbConfig.QueryTimeout
+
+}
+
+// SetPRsToDB inserts or updates pull requests in one transaction, either all of them are written or none
+func SetPRsToDB(ctx context.Context, prs []models.PullRequest) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "SetPRsToDB", "")
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, writeTimeout(len(prs))) // Create context with timeout
+
+	defer cancel()
+
+	tx, err := DB.Begin(dbCtx)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetPRsToDB", "")
+
+		return err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	for _, pr := range prs {
+
+		err = writePR(dbCtx, tx, pr)
 
 		if err != nil {
 
-			logger.Error(err, err.Error())
+			logQueryError(ctx, err, "SetPRsToDB", pr.PullRequestID)
 
 			return err
 
 		}
 
-		createdAt = t
+	}
+
+	return tx.Commit(dbCtx)
+
+}
+
+// CreatePRsToDB inserts new pull requests in one transaction, either all of them are written or none
+// A pull request that already exists, even one written concurrently by another instance, fails with *errs.PRExistsFailure
+func CreatePRsToDB(ctx context.Context, prs []models.PullRequest) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
 
-	} else {
+		logQueryError(ctx, err, "CreatePRsToDB", "")
 
-		createdAt = nil
+		return err
 
 	}
 
-	if pr.MergedAt != "" {
+	dbCtx, cancel := context.WithTimeout(ctx, writeTimeout(len(prs))) // Create context with timeout
 
-		t, err := time.Parse(time.RFC3339, pr.MergedAt)
+	defer cancel()
+
+	tx, err := DB.Begin(dbCtx)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "CreatePRsToDB", "")
+
+		return err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	for _, pr := range prs {
+
+		err = insertPR(dbCtx, tx, pr)
+
+		if errors.Is(err, errs.ErrPRExists) { // Expected outcome of a race, not a database failure
+
+			return err
+
+		}
 
 		if err != nil {
 
-			logger.Error(err, err.Error())
+			logQueryError(ctx, err, "CreatePRsToDB", pr.PullRequestID)
 
 			return err
 
 		}
 
+	}
+
+	return tx.Commit(dbCtx)
+
+}
+
+// insertPR inserts a new pull request with its reviewers in tx, an existing row is left untouched
+func insertPR(ctx context.Context, tx pgx.Tx, pr models.PullRequest) error {
+
+	createdAt, mergedAt, err := prTimes(pr)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	var inserted bool
+
+	// Insert only, so a pull request created concurrently is never overwritten
+	err = tx.QueryRow(ctx, `
+
+        INSERT INTO pull_requests 
+
+        (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, priority, labels)
+
+        VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'normal'), $8)
+
+        ON CONFLICT (pull_request_id) DO NOTHING
+
+        RETURNING true`,
+
+		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status,
+
+		createdAt, mergedAt, pr.Priority, emptyIfNil(pr.Labels)).Scan(&inserted)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+
+		return &errs.PRExistsFailure{PullRequestID: pr.PullRequestID}
+
+	}
+
+	if err != nil {
+
+		return err
+
+	}
+
+	err = syncReviewers(ctx, tx, pr.PullRequestID, pr.AssignedReviewers)
+
+	if err != nil {
+
+		return err
+
+	}
+
+	return notifyInvalidation(ctx, tx, InvalidatePR, pr.PullRequestID) // Other instances may have cached the PR as missing
+
+}
+
+// prTimes converts the RFC3339 timestamps of pr to database values, empty ones become NULL
+func prTimes(pr models.PullRequest) (interface{}, interface{}, error) {
+
+	var createdAt, mergedAt interface{} // Prepare timestamp fields for database
+
+	if pr.CreatedAt != "" {
+
+		t, err := time.Parse(time.RFC3339, pr.CreatedAt)
+
+		if err != nil {
+
+			return nil, nil, err
+
+		}
+
+		createdAt = t
+
+	}
+
+	if pr.MergedAt != "" {
+
+		t, err := time.Parse(time.RFC3339, pr.MergedAt)
+
+		if err != nil {
+
+			return nil, nil, err
+
+		}
+
 		mergedAt = t
 
-	} else {
+	}
+
+	return createdAt, mergedAt, nil
+
+}
+
+// writePR upserts a pull request with its reviewers in tx and notifies other instances on commit
+// New pull requests go through insertPR, so only existing ones are expected here
+func writePR(ctx context.Context, tx pgx.Tx, pr models.PullRequest) error {
+
+	createdAt, mergedAt, err := prTimes(pr)
 
-		mergedAt = nil
+	if err != nil {
+
+		return err
 
 	}
 
 	// Execute UPSERT query - insert new PR or update existing one
-	_, err = DB.Exec(dbCtx, `
+	_, err = tx.Exec(ctx, `
 
         INSERT INTO pull_requests 
 
-        (pull_request_id, pull_request_name, author_id, status, assigned_reviewers, created_at, merged_at)
+        (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, priority, labels)
 
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'normal'), $8)
 
         ON CONFLICT (pull_request_id) DO UPDATE SET
 
@@ -161,22 +382,111 @@ func SetPRToDB(ctx context.Context, pr models.PullRequest) error {
 
             status = EXCLUDED.status,
 
-            assigned_reviewers = EXCLUDED.assigned_reviewers,
+            merged_at = EXCLUDED.merged_at,
+
+            priority = EXCLUDED.priority,
 
-            merged_at = EXCLUDED.merged_at`,
+            labels = EXCLUDED.labels`,
 
 		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status,
 
-		pr.AssignedReviewers, createdAt, mergedAt)
+		createdAt, mergedAt, pr.Priority, emptyIfNil(pr.Labels))
+
+	if err != nil {
+
+		return err
+
+	}
+
+	err = syncReviewers(ctx, tx, pr.PullRequestID, pr.AssignedReviewers)
 
 	if err != nil {
 
-		logger.Error(err, err.Error())
+		return err
+
+	}
+
+	return notifyInvalidation(ctx, tx, InvalidatePR, pr.PullRequestID) // Let other instances drop the stale PR
+
+}
+
+// syncReviewers brings pr_reviewers in line with the given reviewer list
+// Reviewers missing from the list are unassigned, the rest keep their original assignment time
+func syncReviewers(ctx context.Context, tx pgx.Tx, prID string, reviewers []string) error {
+
+	if reviewers == nil {
+
+		reviewers = []string{}
+
+	}
+
+	// Unassign reviewers that are no longer in the list
+	_, err := tx.Exec(ctx, `
+
+        UPDATE pr_reviewers SET state = $3
+
+        WHERE pull_request_id = $1 AND state = $4 AND NOT (user_id = ANY($2))`,
+
+		prID, reviewers, ReviewerUnassigned, ReviewerAssigned)
+
+	if err != nil {
 
 		return err
 
 	}
 
-	return nil
+	if len(reviewers) == 0 {
+
+		return nil
+
+	}
+
+	// Insert new reviewers or reactivate previously unassigned ones, all in one statement with the list order as slot
+	_, err = tx.Exec(ctx, `
+
+        INSERT INTO pr_reviewers (pull_request_id, user_id, slot, state, assigned_at, assigned_by)
+
+        SELECT $1, r.user_id, r.slot - 1, $3, NOW(), $4
+
+        FROM unnest($2::text[]) WITH ORDINALITY AS r(user_id, slot)
+
+        ON CONFLICT (pull_request_id, user_id) DO UPDATE SET
+
+            slot = EXCLUDED.slot,
+
+            state = EXCLUDED.state,
+
+            assigned_at = CASE WHEN pr_reviewers.state = EXCLUDED.state
+
+                THEN pr_reviewers.assigned_at ELSE EXCLUDED.assigned_at END,
+
+            assigned_by = CASE WHEN pr_reviewers.state = EXCLUDED.state
+
+                THEN pr_reviewers.assigned_by ELSE EXCLUDED.assigned_by END,
+
+            reminded_at = CASE WHEN pr_reviewers.state = EXCLUDED.state
+
+                THEN pr_reviewers.reminded_at ELSE NULL END,
+
+            escalated_at = CASE WHEN pr_reviewers.state = EXCLUDED.state
+
+                THEN pr_reviewers.escalated_at ELSE NULL END`,
+
+		prID, reviewers, ReviewerAssigned, AssignedBySystem)
+
+	return err
+
+}
+
+// emptyIfNil keeps nil lists from reaching NOT NULL array columns such as labels and skills
+func emptyIfNil(values []string) []string {
+
+	if values == nil {
+
+		return []string{}
+
+	}
+
+	return values
 
 }
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Postgres exposes the database functions as a repository implementation
+type Postgres struct{}
+
+func (Postgres) GetTeam(ctx context.Context, teamName string) (models.Team, error, bool) {
+
+	return GetTeamFromDB(ctx, teamName)
+
+}
+
+func (Postgres) SetTeam(ctx context.Context, team models.Team) ([]string, error) {
+
+	return SetTeamToDB(ctx, team)
+
+}
+
+func (Postgres) CreateTeam(ctx context.Context, team models.Team) error {
+
+	return CreateTeamToDB(ctx, team)
+
+}
+
+func (Postgres) GetUser(ctx context.Context, userID string) (models.User, error, bool) {
+
+	return GetUserFromDB(ctx, userID)
+
+}
+
+func (Postgres) GetUserReviews(ctx context.Context, userID string, filter models.ReviewFilter) (models.UserRequests, error) {
+
+	return GetPRFromDBByUser(ctx, userID, filter)
+
+}
+
+func (Postgres) GetPR(ctx context.Context, prID string) (models.PullRequest, error, bool) {
+
+	return GetPRFromDB(ctx, prID)
+
+}
+
+func (Postgres) SetPR(ctx context.Context, pr models.PullRequest) error {
+
+	return SetPRToDB(ctx, pr)
+
+}
+
+// CreatePRs inserts new pull requests in one transaction
+func (Postgres) CreatePRs(ctx context.Context, prs []models.PullRequest) error {
+
+	return CreatePRsToDB(ctx, prs)
+
+}
+
+// SetPRs writes pull requests in one transaction
+func (Postgres) SetPRs(ctx context.Context, prs []models.PullRequest) error {
+
+	return SetPRsToDB(ctx, prs)
+
+}
+
+func (Postgres) ListTeams(ctx context.Context, limit int) ([]models.Team, error) {
+
+	teams := []models.Team{}
+
+	err := LoadTeamsFromDB(ctx, limit, func(team models.Team) { teams = append(teams, team) })
+
+	return teams, err
+
+}
+
+func (Postgres) ListPRs(ctx context.Context, limit int, status string) ([]models.PullRequest, error) {
+
+	prs := []models.PullRequest{}
+
+	err := LoadPRsFromDB(ctx, limit, status, func(pr models.PullRequest) { prs = append(prs, pr) })
+
+	return prs, err
+
+}
+
+func (Postgres) GetStats(ctx context.Context) (models.Stats, error) {
+
+	return GetStatsFromDB(ctx)
+
+}
+
+func (Postgres) Export(ctx context.Context, emit func(models.DumpRecord) error) error {
+
+	return ExportFromDB(ctx, emit)
+
+}
+
+func (Postgres) Import(ctx context.Context, dump models.Dump, policy string) (models.ImportResult, error) {
+
+	return ImportToDB(ctx, dump, policy)
+
+}
+
+func (Postgres) StaleReviews(ctx context.Context, defaultSLA time.Duration, teamName string, limit int) ([]models.StaleReview, error) {
+
+	return StaleReviewsFromDB(ctx, defaultSLA, teamName, limit)
+
+}
+
+func (Postgres) ClaimReminders(ctx context.Context, defaultSLA time.Duration, remindEvery time.Duration, limit int) ([]models.StaleReview, bool, error) {
+
+	return ClaimRemindersFromDB(ctx, defaultSLA, remindEvery, limit)
+
+}
+
+func (Postgres) GetTeamPolicy(ctx context.Context, teamName string) (models.TeamPolicy, error, bool) {
+
+	return GetTeamPolicyFromDB(ctx, teamName)
+
+}
+
+func (Postgres) SetTeamPolicy(ctx context.Context, policy models.TeamPolicy) error {
+
+	return SetTeamPolicyToDB(ctx, policy)
+
+}
+
+func (Postgres) ClaimEscalations(ctx context.Context, limit int) ([]models.StaleReview, bool, error) {
+
+	return ClaimEscalationsFromDB(ctx, limit)
+
+}
+
+func (Postgres) SetPRWithHistory(ctx context.Context, pr models.PullRequest, entry models.PRHistoryEntry) error {
+
+	return SetPRWithHistoryToDB(ctx, pr, entry)
+
+}
+
+func (Postgres) GetPRHistory(ctx context.Context, prID string) ([]models.PRHistoryEntry, error) {
+
+	return GetPRHistoryFromDB(ctx, prID)
+
+}
+
+func (Postgres) RecentPairs(ctx context.Context, authorID string, window time.Duration) (map[string]int, error) {
+
+	return RecentPairsFromDB(ctx, authorID, window)
+
+}
@@ -0,0 +1,393 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// slaLockKey is the advisory lock held by the instance sending SLA reminders
+const slaLockKey int64 = 0x50525f534c41 // "PR_SLA"
+
+// staleReviews selects assigned reviews of OPEN pull requests past the SLA of the reviewer's team
+// $1 is the default SLA in seconds for teams without a policy
+const staleReviews = `
+
+        SELECT r.pull_request_id, pr.pull_request_name, pr.author_id, r.user_id, t.team_name,
+
+               EXTRACT(EPOCH FROM NOW() - r.assigned_at)::BIGINT AS age_seconds,
+
+               COALESCE(NULLIF(p.review_sla_seconds, 0), $1) AS sla_seconds
+
+        FROM pr_reviewers r
+
+        JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+
+        JOIN users u ON u.user_id = r.user_id
+
+        JOIN teams t ON t.team_id = u.team_id
+
+        LEFT JOIN team_policies p ON p.team_name = t.team_name
+
+        WHERE r.state = 'ASSIGNED' AND pr.status = 'OPEN'
+
+          AND r.assigned_at < NOW() - make_interval(secs => COALESCE(NULLIF(p.review_sla_seconds, 0), $1))`
+
+// StaleReviewsFromDB lists overdue reviews oldest first, optionally of one team
+func StaleReviewsFromDB(ctx context.Context, defaultSLA time.Duration, teamName string, limit int) ([]models.StaleReview, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "StaleReviewsFromDB", teamName)
+
+		return nil, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	rows, err := DB.Query(dbCtx, staleReviews+`
+
+          AND ($2 = '' OR t.team_name = $2)
+
+        ORDER BY r.assigned_at
+
+        LIMIT $3`, int64(defaultSLA.Seconds()), teamName, limit)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "StaleReviewsFromDB", teamName)
+
+		return nil, err
+
+	}
+
+	reviews, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.StaleReview])
+
+	if err != nil {
+
+		logQueryError(ctx, err, "StaleReviewsFromDB", teamName)
+
+		return nil, err
+
+	}
+
+	return reviews, nil
+
+}
+
+// ClaimRemindersFromDB marks up to limit overdue reviews not reminded within remindEvery as reminded and returns them
+// Only the instance holding the SLA advisory lock claims anything, the others get false
+// The reminders are notified on EventsChannel in the claiming transaction, so every instance delivers them
+// and a claim that fails to notify is rolled back and retried on the next tick
+func ClaimRemindersFromDB(ctx context.Context, defaultSLA time.Duration, remindEvery time.Duration, limit int) ([]models.StaleReview, bool, error) {
+
+	return claimStale(ctx, "ClaimRemindersFromDB", notifyReminders, `
+
+        WITH stale AS (`+staleReviews+`
+
+              AND (r.reminded_at IS NULL OR r.reminded_at < NOW() - make_interval(secs => $2))
+
+            ORDER BY r.assigned_at
+
+            LIMIT $3
+
+            FOR UPDATE OF r
+
+        )
+
+        UPDATE pr_reviewers r SET reminded_at = NOW()
+
+        FROM stale s
+
+        WHERE r.pull_request_id = s.pull_request_id AND r.user_id = s.user_id
+
+        RETURNING s.pull_request_id, s.pull_request_name, s.author_id, s.user_id, s.team_name, s.age_seconds, s.sla_seconds`,
+
+		int64(defaultSLA.Seconds()), remindEvery.Seconds(), limit)
+
+}
+
+// ClaimEscalationsFromDB marks up to limit reviews due for automatic escalation and returns them
+// A review is due when its team policy enables escalation, the reviewer has not acted within the threshold,
+// the pull request has fewer escalations in pr_history than allowed and no attempt was made within the threshold
+func ClaimEscalationsFromDB(ctx context.Context, limit int) ([]models.StaleReview, bool, error) {
+
+	return claimStale(ctx, "ClaimEscalationsFromDB", nil, `
+
+        WITH due AS (
+
+            SELECT r.pull_request_id, pr.pull_request_name, pr.author_id, r.user_id, t.team_name,
+
+                   EXTRACT(EPOCH FROM NOW() - r.assigned_at)::BIGINT AS age_seconds,
+
+                   p.escalate_after_seconds AS sla_seconds
+
+            FROM pr_reviewers r
+
+            JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+
+            JOIN users u ON u.user_id = r.user_id
+
+            JOIN teams t ON t.team_id = u.team_id
+
+            JOIN team_policies p ON p.team_name = t.team_name
+
+            WHERE r.state = 'ASSIGNED' AND pr.status = 'OPEN' AND p.escalate_after_seconds > 0
+
+              AND r.assigned_at < NOW() - make_interval(secs => p.escalate_after_seconds)
+
+              AND (r.escalated_at IS NULL OR r.escalated_at < NOW() - make_interval(secs => p.escalate_after_seconds))
+
+              AND (SELECT COUNT(*) FROM pr_history h
+
+                   WHERE h.pull_request_id = r.pull_request_id AND h.action = 'escalated') < p.max_escalations
+
+            ORDER BY r.assigned_at
+
+            LIMIT $1
+
+            FOR UPDATE OF r
+
+        )
+
+        UPDATE pr_reviewers r SET escalated_at = NOW()
+
+        FROM due s
+
+        WHERE r.pull_request_id = s.pull_request_id AND r.user_id = s.user_id
+
+        RETURNING s.pull_request_id, s.pull_request_name, s.author_id, s.user_id, s.team_name, s.age_seconds, s.sla_seconds`,
+
+		limit)
+
+}
+
+// notifyReminders queues a reminder event for each claimed review, delivered when the transaction commits
+func notifyReminders(ctx context.Context, tx pgx.Tx, reviews []models.StaleReview) error {
+
+	for _, review := range reviews {
+
+		payload, err := json.Marshal(events.Reminder(review))
+
+		if err != nil {
+
+			return err
+
+		}
+
+		if _, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, EventsChannel, string(payload)); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return nil
+
+}
+
+// claimStale runs a claiming query under the SLA advisory lock, false means another instance holds it
+// onClaimed, if set, runs in the same transaction before the claim is committed
+func claimStale(ctx context.Context, name string, onClaimed func(context.Context, pgx.Tx, []models.StaleReview) error, query string, args ...interface{}) ([]models.StaleReview, bool, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, name, "")
+
+		return nil, false, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	tx, err := DB.Begin(dbCtx)
+
+	if err != nil {
+
+		logQueryError(ctx, err, name, "")
+
+		return nil, false, err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	var locked bool
+
+	// Released on commit, so a crashed instance never keeps the scheduler stopped
+	err = tx.QueryRow(dbCtx, `SELECT pg_try_advisory_xact_lock($1)`, slaLockKey).Scan(&locked)
+
+	if err != nil || !locked {
+
+		if err != nil {
+
+			logQueryError(ctx, err, name, "")
+
+		}
+
+		return nil, false, err
+
+	}
+
+	rows, err := tx.Query(dbCtx, query, args...)
+
+	if err != nil {
+
+		logQueryError(ctx, err, name, "")
+
+		return nil, true, err
+
+	}
+
+	reviews, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.StaleReview])
+
+	if err != nil {
+
+		logQueryError(ctx, err, name, "")
+
+		return nil, true, err
+
+	}
+
+	if onClaimed != nil {
+
+		if err = onClaimed(dbCtx, tx, reviews); err != nil {
+
+			logQueryError(ctx, err, name, "")
+
+			return nil, true, err
+
+		}
+
+	}
+
+	return reviews, true, tx.Commit(dbCtx)
+
+}
+
+// GetTeamPolicyFromDB returns the policy of a team, found is false when the team has none
+func GetTeamPolicyFromDB(ctx context.Context, teamName string) (models.TeamPolicy, error, bool) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "GetTeamPolicyFromDB", teamName)
+
+		return models.TeamPolicy{}, err, false
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	policy := models.TeamPolicy{TeamName: teamName}
+
+	var slaSeconds, escalateSeconds int64
+
+	err = DB.QueryRow(dbCtx, `
+
+        SELECT review_sla_seconds, escalate_after_seconds, max_escalations, min_senior_reviewers, skill_labels
+
+        FROM team_policies WHERE team_name = $1`, teamName).Scan(&slaSeconds, &escalateSeconds, &policy.MaxEscalations,
+
+		&policy.MinSeniorReviewers, &policy.SkillLabels)
+
+	if errors.Is(err, pgx.ErrNoRows) { // No policy yet, defaults apply
+
+		return policy, nil, false
+
+	}
+
+	if err != nil {
+
+		logQueryError(ctx, err, "GetTeamPolicyFromDB", teamName)
+
+		return models.TeamPolicy{}, err, false
+
+	}
+
+	policy.ReviewSLAHours = int(slaSeconds / 3600)
+
+	policy.EscalateAfterHours = int(escalateSeconds / 3600)
+
+	return policy, nil, true
+
+}
+
+// SetTeamPolicyToDB inserts or replaces the policy of a team
+func SetTeamPolicyToDB(ctx context.Context, policy models.TeamPolicy) error {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "SetTeamPolicyToDB", policy.TeamName)
+
+		return err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	_, err = DB.Exec(dbCtx, `
+
+        INSERT INTO team_policies (team_name, review_sla_seconds, escalate_after_seconds, max_escalations, min_senior_reviewers, skill_labels)
+
+        VALUES ($1, $2, $3, $4, $5, $6)
+
+        ON CONFLICT (team_name) DO UPDATE SET
+
+            review_sla_seconds = EXCLUDED.review_sla_seconds,
+
+            escalate_after_seconds = EXCLUDED.escalate_after_seconds,
+
+            max_escalations = EXCLUDED.max_escalations,
+
+            min_senior_reviewers = EXCLUDED.min_senior_reviewers,
+
+            skill_labels = EXCLUDED.skill_labels`,
+
+		policy.TeamName, int64(policy.ReviewSLAHours)*3600, int64(policy.EscalateAfterHours)*3600, policy.MaxEscalations,
+
+		policy.MinSeniorReviewers, emptyIfNil(policy.SkillLabels))
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetTeamPolicyToDB", policy.TeamName)
+
+		return err
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// GetStatsFromDB aggregates team, user, pull request and reviewer counters
+func GetStatsFromDB(ctx context.Context) (models.Stats, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "GetStatsFromDB", "")
+
+		return models.Stats{}, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	stats := models.Stats{PullRequests: map[string]int{}, Reviewers: []models.ReviewerStats{}}
+
+	// Query entity counters in one round-trip
+	err = DB.QueryRow(dbCtx, `
+
+        SELECT (SELECT COUNT(*) FROM teams),
+
+               (SELECT COUNT(*) FROM users),
+
+               (SELECT COUNT(*) FROM users WHERE is_active)`).Scan(
+
+		&stats.Teams, &stats.Users, &stats.ActiveUsers)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "GetStatsFromDB", "")
+
+		return models.Stats{}, err
+
+	}
+
+	rows, err := DB.Query(dbCtx, `SELECT status, COUNT(*) FROM pull_requests GROUP BY status`)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "GetStatsFromDB", "")
+
+		return models.Stats{}, err
+
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var status string
+
+		var count int
+
+		if err := rows.Scan(&status, &count); err != nil {
+
+			logQueryError(ctx, err, "GetStatsFromDB", "")
+
+			return models.Stats{}, err
+
+		}
+
+		stats.PullRequests[status] = count
+
+	}
+
+	// Query review load and declines for every user who was ever assigned, a declined review stays as UNASSIGNED
+	reviewerRows, err := DB.Query(dbCtx, `
+
+        SELECT r.user_id,
+
+               COUNT(*) FILTER (WHERE r.state = $1 AND pr.status = 'OPEN'),
+
+               COUNT(*) FILTER (WHERE r.state = $1),
+
+               COALESCE(d.declined, 0),
+
+               COALESCE(d.declined, 0)::FLOAT8 / COUNT(*)
+
+        FROM pr_reviewers r
+
+        JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+
+        LEFT JOIN (
+
+            SELECT user_id, COUNT(*) AS declined FROM pr_history WHERE action = 'declined' GROUP BY user_id
+
+        ) d ON d.user_id = r.user_id
+
+        GROUP BY r.user_id, d.declined
+
+        ORDER BY 2 DESC, r.user_id`, ReviewerAssigned)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "GetStatsFromDB", "")
+
+		return models.Stats{}, err
+
+	}
+
+	defer reviewerRows.Close()
+
+	for reviewerRows.Next() {
+
+		var reviewer models.ReviewerStats
+
+		if err := reviewerRows.Scan(&reviewer.UserID, &reviewer.OpenReviews, &reviewer.TotalReviews, &reviewer.Declined, &reviewer.DeclineRate); err != nil {
+
+			logQueryError(ctx, err, "GetStatsFromDB", "")
+
+			return models.Stats{}, err
+
+		}
+
+		stats.Reviewers = append(stats.Reviewers, reviewer)
+
+	}
+
+	return stats, nil
+
+}
+
+// OpenReviewsByTeamFromDB counts assigned reviews of OPEN pull requests by the reviewer's team
+func OpenReviewsByTeamFromDB(ctx context.Context) (map[string]int, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		return nil, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	rows, err := DB.Query(dbCtx, `
+
+        SELECT t.team_name, COUNT(*)
+
+        FROM pr_reviewers r
+
+        JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+
+        JOIN users u ON u.user_id = r.user_id
+
+        JOIN teams t ON t.team_id = u.team_id
+
+        WHERE r.state = $1 AND pr.status = 'OPEN'
+
+        GROUP BY t.team_name`, ReviewerAssigned)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "OpenReviewsByTeamFromDB", "")
+
+		return nil, err
+
+	}
+
+	defer rows.Close()
+
+	res := make(map[string]int)
+
+	for rows.Next() {
+
+		var team string
+
+		var count int
+
+		if err := rows.Scan(&team, &count); err != nil {
+
+			logQueryError(ctx, err, "OpenReviewsByTeamFromDB", "")
+
+			return nil, err
+
+		}
+
+		res[team] = count
+
+	}
+
+	return res, rows.Err()
+
+}
@@ -2,17 +2,19 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 )
 
 // GetTeamFromDB retrieves a team and its members from the database by team name
 func GetTeamFromDB(ctx context.Context, teamName string) (models.Team, error, bool) {
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
 
 	defer cancel()
 
@@ -22,7 +24,7 @@ func GetTeamFromDB(ctx context.Context, teamName string) (models.Team, error, bo
 
 		err = fmt.Errorf("database not initialized")
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetTeamFromDB", teamName)
 
 		return models.Team{}, err, false
 
@@ -35,7 +37,7 @@ func GetTeamFromDB(ctx context.Context, teamName string) (models.Team, error, bo
 	// Query all users belonging to the specified team
 	rows, err := DB.Query(dbCtx, `
 
-        SELECT u.user_id, u.username, u.is_active 
+        SELECT u.user_id, u.username, u.is_active, u.level, u.skills
 
         FROM users u 
 
@@ -45,7 +47,7 @@ func GetTeamFromDB(ctx context.Context, teamName string) (models.Team, error, bo
 
 	if err != nil {
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetTeamFromDB", teamName)
 
 		return models.Team{}, fmt.Errorf("failed to get team: %w", err), false
 
@@ -58,11 +60,11 @@ func GetTeamFromDB(ctx context.Context, teamName string) (models.Team, error, bo
 
 		var user models.User
 
-		err := rows.Scan(&user.UserID, &user.Username, &user.IsActive)
+		err := rows.Scan(&user.UserID, &user.Username, &user.IsActive, &user.Level, &user.Skills)
 
 		if err != nil {
 
-			logger.Error(err, err.Error())
+			logQueryError(ctx, err, "GetTeamFromDB", teamName)
 
 			return models.Team{}, err, false
 
@@ -78,8 +80,10 @@ func GetTeamFromDB(ctx context.Context, teamName string) (models.Team, error, bo
 
 }
 
-// SetTeamToDB creates or updates a team and all its members in the database
-func SetTeamToDB(ctx context.Context, team models.Team) error {
+// CreateTeamToDB inserts a new team with new members and never updates existing rows,
+// so of two concurrent creates only one succeeds. A taken team name returns *errs.TeamExistsFailure,
+// a member that already exists *errs.UserExistsFailure
+func CreateTeamToDB(ctx context.Context, team models.Team) error {
 
 	var err error
 
@@ -87,21 +91,21 @@ func SetTeamToDB(ctx context.Context, team models.Team) error {
 
 		err = fmt.Errorf("database not initialized")
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
 
 		return err
 
 	}
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
 
 	defer cancel()
 
-	tx, err := DB.Begin(dbCtx) // Begin transaction to ensure team creation/update
+	tx, err := DB.Begin(dbCtx)
 
 	if err != nil {
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
 
 		return err
 
@@ -111,6 +115,164 @@ func SetTeamToDB(ctx context.Context, team models.Team) error {
 
 	var teamID int
 
+	// Insert only, a concurrent create of the same team waits for this one and finds the name taken
+	err = tx.QueryRow(dbCtx, `
+
+        INSERT INTO teams (team_name)
+
+        VALUES ($1)
+
+        ON CONFLICT (team_name) DO NOTHING
+
+        RETURNING team_id`, team.TeamName).Scan(&teamID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+
+		return &errs.TeamExistsFailure{TeamName: team.TeamName}
+
+	}
+
+	if err != nil {
+
+		logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
+
+		return err
+
+	}
+
+	for _, member := range team.Members {
+
+		var inserted bool
+
+		// Members are never moved out of another team here
+		err = tx.QueryRow(dbCtx, `
+
+            INSERT INTO users (user_id, username, team_id, is_active, level, skills)
+
+            VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'middle'), $6)
+
+            ON CONFLICT (user_id) DO NOTHING
+
+            RETURNING true`,
+
+			member.UserID, member.Username, teamID, member.IsActive, member.Level, emptyIfNil(member.Skills)).Scan(&inserted)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+
+			return &errs.UserExistsFailure{UserID: member.UserID}
+
+		}
+
+		if err != nil {
+
+			logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
+
+			return err
+
+		}
+
+		if err := notifyInvalidation(dbCtx, tx, InvalidateUser, member.UserID); err != nil {
+
+			logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
+
+			return err
+
+		}
+
+	}
+
+	// Other instances may have cached the team as missing members or the users as unknown
+	if err := notifyInvalidation(dbCtx, tx, InvalidateTeam, team.TeamName); err != nil {
+
+		logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
+
+		return err
+
+	}
+
+	if err := tx.Commit(dbCtx); err != nil {
+
+		logQueryError(ctx, err, "CreateTeamToDB", team.TeamName)
+
+		return err
+
+	}
+
+	return nil
+
+}
+
+// SetTeamToDB creates or updates a team and all its members in the database
+// It returns the teams that members were moved out of
+func SetTeamToDB(ctx context.Context, team models.Team) ([]string, error) {
+
+	var err error
+
+	if DB == nil { // Check if database connection is initialized
+
+		err = fmt.Errorf("database not initialized")
+
+		logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
+
+		return nil, err
+
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
+
+	defer cancel()
+
+	tx, err := DB.Begin(dbCtx) // Begin transaction to ensure team creation/update
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
+
+		return nil, err
+
+	}
+
+	defer tx.Rollback(dbCtx) // Ensure rollback if transaction fails
+
+	memberIDs := make([]string, 0, len(team.Members))
+
+	for _, member := range team.Members {
+
+		memberIDs = append(memberIDs, member.UserID)
+
+	}
+
+	// Collect teams that members are moved out of, their cached member lists become stale
+	rows, err := tx.Query(dbCtx, `
+
+        SELECT DISTINCT t.team_name
+
+        FROM users u
+
+        JOIN teams t ON u.team_id = t.team_id
+
+        WHERE u.user_id = ANY($1) AND t.team_name <> $2`, memberIDs, team.TeamName)
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
+
+		return nil, err
+
+	}
+
+	staleTeams, err := pgx.CollectRows(rows, pgx.RowTo[string])
+
+	if err != nil {
+
+		logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
+
+		return nil, err
+
+	}
+
+	var teamID int
+
 	// Insert or update team, returning the team_id for user associations
 	err = tx.QueryRow(dbCtx, `
 
@@ -124,9 +286,9 @@ func SetTeamToDB(ctx context.Context, team models.Team) error {
 
 	if err != nil {
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
 
-		return err
+		return nil, err
 
 	}
 
@@ -135,9 +297,9 @@ func SetTeamToDB(ctx context.Context, team models.Team) error {
 
 		_, err := tx.Exec(dbCtx, `
 
-            INSERT INTO users (user_id, username, team_id, is_active) 
+            INSERT INTO users (user_id, username, team_id, is_active, level, skills) 
 
-            VALUES ($1, $2, $3, $4)
+            VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'middle'), $6)
 
             ON CONFLICT (user_id) DO UPDATE SET 
 
@@ -145,21 +307,56 @@ func SetTeamToDB(ctx context.Context, team models.Team) error {
 
                 team_id = EXCLUDED.team_id,
 
-                is_active = EXCLUDED.is_active`,
+                is_active = EXCLUDED.is_active,
+
+                level = EXCLUDED.level,
+
+                skills = EXCLUDED.skills`,
 
-			member.UserID, member.Username, teamID, member.IsActive)
+			member.UserID, member.Username, teamID, member.IsActive, member.Level, emptyIfNil(member.Skills))
 
 		if err != nil {
 
-			logger.Error(err, err.Error())
+			logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
 
-			return err
+			return nil, err
+
+		}
+
+	}
+
+	// Let other instances drop the stale team, its members and the teams they left
+	for _, teamName := range append(staleTeams, team.TeamName) {
+
+		if err := notifyInvalidation(dbCtx, tx, InvalidateTeam, teamName); err != nil {
+
+			logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
+
+			return nil, err
+
+		}
+
+	}
+
+	for _, userID := range memberIDs {
+
+		if err := notifyInvalidation(dbCtx, tx, InvalidateUser, userID); err != nil {
+
+			logQueryError(ctx, err, "SetTeamToDB", team.TeamName)
+
+			return nil, err
 
 		}
 
 	}
 
 	// Commit transaction
-	return tx.Commit(dbCtx)
+	if err := tx.Commit(dbCtx); err != nil {
+
+		return nil, err
+
+	}
+
+	return staleTeams, nil
 
 }
@@ -7,13 +7,12 @@ import (
 
 	"github.com/jackc/pgx/v5"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
 )
 
-// GetPRFromDBByUser retrieves all pull requests where the specified user is assigned as a reviewer
-func GetPRFromDBByUser(ctx context.Context, userID string) (models.UserRequests, error) {
+// GetPRFromDBByUser retrieves pull requests where the specified user is assigned as a reviewer
+// Open pull requests come first, then higher priorities, then older ones
+func GetPRFromDBByUser(ctx context.Context, userID string, filter models.ReviewFilter) (models.UserRequests, error) {
 
 	var err error
 
@@ -21,13 +20,13 @@ func GetPRFromDBByUser(ctx context.Context, userID string) (models.UserRequests,
 
 		err = fmt.Errorf("database not initialized")
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetPRFromDBByUser", userID)
 
 		return models.UserRequests{}, err
 
 	}
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
 
 	defer cancel()
 
@@ -35,20 +34,34 @@ func GetPRFromDBByUser(ctx context.Context, userID string) (models.UserRequests,
 
 	userRequests.UserID = userID
 
-	// Query all PRs where the user is in the assigned_reviewers JSONB array
+	userRequests.PullRequests = []models.PullRequestShort{}
+
+	// Query matching PRs where the user is currently an assigned reviewer
 	rows, err := DB.Query(dbCtx, `
 
-        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.priority, pr.labels
 
         FROM pull_requests pr
 
-        WHERE pr.assigned_reviewers @> $1`,
+        JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+
+        WHERE r.user_id = $1 AND r.state = $2
+
+          AND ($3 = '' OR pr.status = $3)
+
+          AND ($4 = '' OR $4 = ANY(pr.labels))
+
+        ORDER BY pr.status = 'OPEN' DESC,
+
+                 array_position(ARRAY['urgent', 'high', 'normal', 'low']::VARCHAR[], pr.priority),
+
+                 pr.created_at NULLS LAST, pr.pull_request_id`,
 
-		fmt.Sprintf(`["%s"]`, userID))
+		userID, ReviewerAssigned, filter.Status, filter.Label)
 
 	if err != nil {
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetPRFromDBByUser", userID)
 
 		return models.UserRequests{}, err
 
@@ -63,11 +76,11 @@ func GetPRFromDBByUser(ctx context.Context, userID string) (models.UserRequests,
 
 		err := rows.Scan(
 
-			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status)
+			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.Priority, &pr.Labels)
 
 		if err != nil {
 
-			logger.Error(err, err.Error())
+			logQueryError(ctx, err, "GetPRFromDBByUser", userID)
 
 			return models.UserRequests{}, err
 
@@ -84,7 +97,7 @@ func GetPRFromDBByUser(ctx context.Context, userID string) (models.UserRequests,
 // GetUserFromDB retrieves a user from the database by user ID
 func GetUserFromDB(ctx context.Context, userID string) (models.User, error, bool) {
 
-	dbCtx, cancel := context.WithTimeout(ctx, config.PostgresTimeOut) // Create context with timeout
+	dbCtx, cancel := context.WithTimeout(ctx, dbConfig.QueryTimeout) // Create context with timeout
 
 	defer cancel()
 
@@ -94,7 +107,7 @@ func GetUserFromDB(ctx context.Context, userID string) (models.User, error, bool
 
 		err = fmt.Errorf("database not initialized")
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetUserFromDB", userID)
 
 		return models.User{}, err, false
 
@@ -105,7 +118,7 @@ func GetUserFromDB(ctx context.Context, userID string) (models.User, error, bool
 	// Query user with team information
 	err = DB.QueryRow(dbCtx, `
 
-        SELECT u.user_id, u.username, u.is_active, t.team_name 
+        SELECT u.user_id, u.username, u.is_active, t.team_name, u.level, u.skills
 
         FROM users u 
 
@@ -113,7 +126,7 @@ func GetUserFromDB(ctx context.Context, userID string) (models.User, error, bool
 
         WHERE u.user_id = $1`, userID).Scan(
 
-		&user.UserID, &user.Username, &user.IsActive, &user.TeamName)
+		&user.UserID, &user.Username, &user.IsActive, &user.TeamName, &user.Level, &user.Skills)
 
 	if err != nil {
 
@@ -123,7 +136,7 @@ func GetUserFromDB(ctx context.Context, userID string) (models.User, error, bool
 
 		}
 
-		logger.Error(err, err.Error())
+		logQueryError(ctx, err, "GetUserFromDB", userID)
 
 		return models.User{}, err, false
 
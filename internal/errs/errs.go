@@ -13,6 +13,11 @@ const (
 	CodeNotFound        ErrorCode = "NOT_FOUND"
 	CodeValidationError ErrorCode = "VALIDATION_ERROR"
 	CodeDatabaseError   ErrorCode = "DATABASE_ERROR"
+	CodeImportConflict  ErrorCode = "IMPORT_CONFLICT"
+	CodeUnavailable     ErrorCode = "UNAVAILABLE"
+	CodeRateLimited     ErrorCode = "RATE_LIMITED"
+	CodePayloadTooLarge ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeUnauthorized    ErrorCode = "UNAUTHORIZED"
 )
 
 var (
@@ -24,15 +29,104 @@ var (
 	ErrNotFound    = errors.New("resource not found")
 	ErrValidation  = errors.New("invalid input data")
 	ErrDatabase    = errors.New("internal database error")
+
+	ErrImportConflict = errors.New("imported entity already exists")
+
+	ErrUnavailable = errors.New("service is not ready")
+
+	ErrRateLimited = errors.New("too many requests, retry later")
+
+	ErrPayloadTooLarge = errors.New("request body is too large")
+
+	ErrUnauthorized = errors.New("missing or invalid admin token")
 )
 
+// FieldError describes a single invalid request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type ErrorResponse struct {
 	Error struct {
-		Code    ErrorCode `json:"code"`
-		Message string    `json:"message"`
+		Code    ErrorCode    `json:"code"`
+		Message string       `json:"message"`
+		Details []FieldError `json:"details,omitempty"`
 	} `json:"error"`
 }
 
+// ValidationFailure is a validation error carrying per-field details
+// It matches ErrValidation with errors.Is
+type ValidationFailure struct {
+	Details []FieldError
+}
+
+func (v *ValidationFailure) Error() string {
+	return ErrValidation.Error()
+}
+
+func (v *ValidationFailure) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// PRExistsFailure is a pull request created concurrently by another request
+// It matches ErrPRExists with errors.Is
+type PRExistsFailure struct {
+	PullRequestID string
+}
+
+func (p *PRExistsFailure) Error() string {
+	return ErrPRExists.Error() + ": " + p.PullRequestID
+}
+
+func (p *PRExistsFailure) Is(target error) bool {
+	return target == ErrPRExists
+}
+
+// TeamExistsFailure is a team created concurrently by another request
+// It matches ErrTeamExists with errors.Is
+type TeamExistsFailure struct {
+	TeamName string
+}
+
+func (t *TeamExistsFailure) Error() string {
+	return ErrTeamExists.Error() + ": " + t.TeamName
+}
+
+func (t *TeamExistsFailure) Is(target error) bool {
+	return target == ErrTeamExists
+}
+
+// UserExistsFailure is a new team member that was added to a team concurrently by another request
+type UserExistsFailure struct {
+	UserID string
+}
+
+func (u *UserExistsFailure) Error() string {
+	return "user already exists: " + u.UserID
+}
+
+// CodeOf maps a service error to its API code, unknown errors are database errors
+func CodeOf(err error) ErrorCode {
+	switch {
+	case errors.Is(err, ErrTeamExists):
+		return CodeTeamExists
+	case errors.Is(err, ErrPRExists):
+		return CodePRExists
+	case errors.Is(err, ErrPRMerged):
+		return CodePRMerged
+	case errors.Is(err, ErrNotAssigned):
+		return CodeNotAssigned
+	case errors.Is(err, ErrNoCandidate):
+		return CodeNoCandidate
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrValidation):
+		return CodeValidationError
+	}
+	return CodeDatabaseError
+}
+
 func NewErrorResponse(code ErrorCode, message string) ErrorResponse {
 	var resp ErrorResponse
 	resp.Error.Code = code
@@ -64,10 +158,33 @@ func NotFound() ErrorResponse {
 	return NewErrorResponse(CodeNotFound, ErrNotFound.Error())
 }
 
-func ValidationError() ErrorResponse {
-	return NewErrorResponse(CodeValidationError, ErrValidation.Error())
+func ValidationError(details ...FieldError) ErrorResponse {
+	resp := NewErrorResponse(CodeValidationError, ErrValidation.Error())
+	resp.Error.Details = details
+	return resp
 }
 
 func DatabaseError() ErrorResponse {
 	return NewErrorResponse(CodeDatabaseError, ErrDatabase.Error())
 }
+
+// ImportConflict reports the entity that stopped an import with the fail policy
+func ImportConflict(message string) ErrorResponse {
+	return NewErrorResponse(CodeImportConflict, message)
+}
+
+func Unavailable() ErrorResponse {
+	return NewErrorResponse(CodeUnavailable, ErrUnavailable.Error())
+}
+
+func RateLimited() ErrorResponse {
+	return NewErrorResponse(CodeRateLimited, ErrRateLimited.Error())
+}
+
+func PayloadTooLarge() ErrorResponse {
+	return NewErrorResponse(CodePayloadTooLarge, ErrPayloadTooLarge.Error())
+}
+
+func Unauthorized() ErrorResponse {
+	return NewErrorResponse(CodeUnauthorized, ErrUnauthorized.Error())
+}
@@ -0,0 +1,212 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Review event types
+const (
+	ReviewAssigned = "assigned"
+
+	ReviewUnassigned = "unassigned"
+
+	ReviewMerged = "merged"
+
+	ReviewReminder = "reminder" // the review is past its team SLA
+)
+
+// subscriberBuffer is how many events a slow subscriber may lag behind before events are dropped
+const subscriberBuffer = 64
+
+// Event is a change in a user's review assignments
+type Event struct {
+	Type string `json:"type"`
+
+	UserID string `json:"user_id"`
+
+	PullRequest models.PullRequestShort `json:"pull_request"`
+
+	AgeSeconds int64 `json:"age_seconds,omitempty"` // time since assignment, set on reminders
+
+	At string `json:"at"`
+}
+
+// Relay sends an event to every instance of the service, each of them hands it to Deliver
+type Relay func(Event) error
+
+// Hub is a publish/subscribe hub of review events keyed by user
+// Without a relay events reach only the subscribers of this process
+type Hub struct {
+	mu sync.RWMutex
+
+	subs map[string]map[chan Event]struct{}
+
+	relay atomic.Pointer[Relay]
+}
+
+// Default is the hub fed by the pullrequest service
+var Default = NewHub()
+
+// constructor
+func NewHub() *Hub {
+
+	return &Hub{subs: make(map[string]map[chan Event]struct{})}
+
+}
+
+// Subscribe returns a channel of events for userID and a function that must be called to unsubscribe
+func (h *Hub) Subscribe(userID string) (<-chan Event, func()) {
+
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+
+	if h.subs[userID] == nil {
+
+		h.subs[userID] = make(map[chan Event]struct{})
+
+	}
+
+	h.subs[userID][ch] = struct{}{}
+
+	h.mu.Unlock()
+
+	var once sync.Once
+
+	return ch, func() {
+
+		once.Do(func() {
+
+			h.mu.Lock()
+
+			delete(h.subs[userID], ch)
+
+			if len(h.subs[userID]) == 0 {
+
+				delete(h.subs, userID)
+
+			}
+
+			h.mu.Unlock()
+
+			close(ch)
+
+		})
+
+	}
+
+}
+
+// SetRelay makes Publish send events through relay instead of delivering them here, nil turns it off
+func (h *Hub) SetRelay(relay Relay) {
+
+	if relay == nil {
+
+		h.relay.Store(nil)
+
+		return
+
+	}
+
+	h.relay.Store(&relay)
+
+}
+
+// Publish sends an event through the relay, or delivers it here when there is none or it failed
+func (h *Hub) Publish(event Event) {
+
+	if event.At == "" {
+
+		event.At = time.Now().UTC().Format(time.RFC3339)
+
+	}
+
+	if relay := h.relay.Load(); relay != nil {
+
+		err := (*relay)(event)
+
+		if err == nil {
+
+			return
+
+		}
+
+		logger.Error(err, "failed to relay review event, delivering it on this instance only", "user_id", event.UserID, "type", event.Type)
+
+	}
+
+	h.Deliver(event)
+
+}
+
+// Deliver hands an event to all subscribers of its user on this instance without blocking
+func (h *Hub) Deliver(event Event) {
+
+	h.mu.RLock()
+
+	defer h.mu.RUnlock()
+
+	for ch := range h.subs[event.UserID] {
+
+		select {
+
+		case ch <- event:
+
+		default:
+
+			logger.Info("review event dropped for slow subscriber", "user_id", event.UserID, "type", event.Type)
+
+		}
+
+	}
+
+}
+
+// PublishReview sends the same event type about pr to each of users
+func (h *Hub) PublishReview(eventType string, pr models.PullRequest, users ...string) {
+
+	short := models.PullRequestShort{
+
+		PullRequestID: pr.PullRequestID,
+
+		PullRequestName: pr.PullRequestName,
+
+		AuthorID: pr.AuthorID,
+
+		Status: pr.Status,
+
+		Priority: pr.Priority,
+
+		Labels: pr.Labels,
+	}
+
+	for _, userID := range users {
+
+		h.Publish(Event{Type: eventType, UserID: userID, PullRequest: short})
+
+	}
+
+}
+
+// Reminder builds the event telling the reviewer of an overdue review about it
+func Reminder(review models.StaleReview) Event {
+
+	short := models.PullRequestShort{
+
+		PullRequestID: review.PullRequestID,
+
+		PullRequestName: review.PullRequestName,
+
+		AuthorID: review.AuthorID,
+
+		Status: "OPEN", // only open pull requests are reminded about
+	}
+
+	return Event{Type: ReviewReminder, UserID: review.ReviewerID, PullRequest: short, AgeSeconds: review.AgeSeconds, At: time.Now().UTC().Format(time.RFC3339)}
+
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+func TestHub_PublishToSubscriber(t *testing.T) {
+
+	hub := NewHub()
+
+	ch, unsubscribe := hub.Subscribe("u1")
+
+	other, unsubscribeOther := hub.Subscribe("u2")
+
+	defer unsubscribeOther()
+
+	hub.PublishReview(ReviewAssigned, models.PullRequest{PullRequestID: "pr1", Status: "OPEN"}, "u1")
+
+	event := <-ch
+
+	assert.Equal(t, ReviewAssigned, event.Type)
+
+	assert.Equal(t, "pr1", event.PullRequest.PullRequestID)
+
+	assert.NotEmpty(t, event.At)
+
+	assert.Empty(t, other)
+
+	unsubscribe()
+
+	unsubscribe()
+
+	_, open := <-ch
+
+	assert.False(t, open)
+
+	hub.PublishReview(ReviewMerged, models.PullRequest{PullRequestID: "pr1"}, "u1")
+
+}
+
+func TestHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+
+	hub := NewHub()
+
+	ch, unsubscribe := hub.Subscribe("u1")
+
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+
+		hub.Publish(Event{Type: ReviewAssigned, UserID: "u1"})
+
+	}
+
+	assert.Len(t, ch, subscriberBuffer)
+
+}
+
+func TestHub_RelayReachesEveryInstance(t *testing.T) {
+
+	local, remote := NewHub(), NewHub()
+
+	// The relay stands in for Postgres NOTIFY, every instance delivers what it receives
+	relayed := 0
+
+	relay := func(event Event) error {
+
+		relayed++
+
+		local.Deliver(event)
+
+		remote.Deliver(event)
+
+		return nil
+
+	}
+
+	local.SetRelay(relay)
+
+	mine, unsubscribeMine := local.Subscribe("u1")
+
+	defer unsubscribeMine()
+
+	theirs, unsubscribeTheirs := remote.Subscribe("u1")
+
+	defer unsubscribeTheirs()
+
+	local.Publish(Event{Type: ReviewAssigned, UserID: "u1"})
+
+	assert.Equal(t, 1, relayed)
+
+	assert.Len(t, mine, 1, "delivered once, through the relay")
+
+	assert.Len(t, theirs, 1)
+
+	local.SetRelay(func(Event) error { return errors.New("connection refused") })
+
+	local.Publish(Event{Type: ReviewMerged, UserID: "u1"})
+
+	assert.Len(t, mine, 2, "a failed relay still reaches local subscribers")
+
+	assert.Len(t, theirs, 1)
+
+}
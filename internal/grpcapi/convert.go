@@ -0,0 +1,78 @@
+package grpcapi
+
+import (
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/grpcapi/pb"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// conversions between protobuf messages and models
+
+func teamFromPB(team *pb.Team) models.Team {
+
+	res := models.Team{TeamName: team.GetTeamName()}
+
+	for _, m := range team.GetMembers() {
+
+		res.Members = append(res.Members, models.TeamMember{UserID: m.GetUserId(), Username: m.GetUsername(), IsActive: m.GetIsActive()})
+
+	}
+
+	return res
+
+}
+
+func teamToPB(team models.Team) *pb.Team {
+
+	res := &pb.Team{TeamName: team.TeamName}
+
+	for _, m := range team.Members {
+
+		res.Members = append(res.Members, &pb.TeamMember{UserId: m.UserID, Username: m.Username, IsActive: m.IsActive})
+
+	}
+
+	return res
+
+}
+
+func userToPB(user models.User) *pb.User {
+
+	return &pb.User{UserId: user.UserID, Username: user.Username, TeamName: user.TeamName, IsActive: user.IsActive}
+
+}
+
+func prToPB(pr models.PullRequest) *pb.PullRequest {
+
+	return &pb.PullRequest{
+
+		PullRequestId: pr.PullRequestID,
+
+		PullRequestName: pr.PullRequestName,
+
+		AuthorId: pr.AuthorID,
+
+		Status: pr.Status,
+
+		AssignedReviewers: pr.AssignedReviewers,
+
+		CreatedAt: pr.CreatedAt,
+
+		MergedAt: pr.MergedAt,
+	}
+
+}
+
+func prShortToPB(pr models.PullRequestShort) *pb.PullRequestShort {
+
+	return &pb.PullRequestShort{
+
+		PullRequestId: pr.PullRequestID,
+
+		PullRequestName: pr.PullRequestName,
+
+		AuthorId: pr.AuthorID,
+
+		Status: pr.Status,
+	}
+
+}
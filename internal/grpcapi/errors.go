@@ -0,0 +1,86 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+)
+
+// errorDomain is reported in ErrorInfo details next to the errs code
+const errorDomain = "pr-reviewer-service"
+
+// toStatus maps a service error to a gRPC status carrying the errs code as ErrorInfo reason
+func toStatus(err error) error {
+
+	code, errCode := codes.Internal, errs.CodeDatabaseError
+
+	switch {
+
+	case errors.Is(err, errs.ErrTeamExists):
+
+		code, errCode = codes.AlreadyExists, errs.CodeTeamExists
+
+	case errors.Is(err, errs.ErrPRExists):
+
+		code, errCode = codes.AlreadyExists, errs.CodePRExists
+
+	case errors.Is(err, errs.ErrPRMerged):
+
+		code, errCode = codes.FailedPrecondition, errs.CodePRMerged
+
+	case errors.Is(err, errs.ErrNotAssigned):
+
+		code, errCode = codes.FailedPrecondition, errs.CodeNotAssigned
+
+	case errors.Is(err, errs.ErrNoCandidate):
+
+		code, errCode = codes.FailedPrecondition, errs.CodeNoCandidate
+
+	case errors.Is(err, errs.ErrNotFound):
+
+		code, errCode = codes.NotFound, errs.CodeNotFound
+
+	case errors.Is(err, errs.ErrValidation):
+
+		code, errCode = codes.InvalidArgument, errs.CodeValidationError
+
+	}
+
+	st := status.New(code, err.Error())
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(errCode), Domain: errorDomain}}
+
+	var failure *errs.ValidationFailure
+
+	if errors.As(err, &failure) {
+
+		badRequest := &errdetails.BadRequest{}
+
+		for _, d := range failure.Details {
+
+			badRequest.FieldViolations = append(badRequest.FieldViolations,
+
+				&errdetails.BadRequest_FieldViolation{Field: d.Field, Description: d.Message})
+
+		}
+
+		details = append(details, badRequest)
+
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+
+	if detailsErr != nil {
+
+		return st.Err()
+
+	}
+
+	return withDetails.Err()
+
+}
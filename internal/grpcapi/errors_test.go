@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+)
+
+func TestToStatus(t *testing.T) {
+
+	cases := []struct {
+		err error
+
+		code codes.Code
+
+		reason errs.ErrorCode
+	}{
+		{errs.ErrTeamExists, codes.AlreadyExists, errs.CodeTeamExists},
+
+		{errs.ErrPRExists, codes.AlreadyExists, errs.CodePRExists},
+
+		{errs.ErrPRMerged, codes.FailedPrecondition, errs.CodePRMerged},
+
+		{errs.ErrNotAssigned, codes.FailedPrecondition, errs.CodeNotAssigned},
+
+		{errs.ErrNoCandidate, codes.FailedPrecondition, errs.CodeNoCandidate},
+
+		{fmt.Errorf("wrapped: %w", errs.ErrNotFound), codes.NotFound, errs.CodeNotFound},
+
+		{errs.ErrDatabase, codes.Internal, errs.CodeDatabaseError},
+	}
+
+	for _, c := range cases {
+
+		st := status.Convert(toStatus(c.err))
+
+		assert.Equal(t, c.code, st.Code(), c.err.Error())
+
+		assert.Equal(t, string(c.reason), st.Details()[0].(*errdetails.ErrorInfo).Reason)
+
+	}
+
+	failure := &errs.ValidationFailure{Details: []errs.FieldError{{Field: "author_id", Message: "is required"}}}
+
+	st := status.Convert(toStatus(failure))
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	violations := st.Details()[1].(*errdetails.BadRequest).FieldViolations
+
+	assert.Equal(t, "author_id", violations[0].Field)
+
+	assert.Equal(t, "is required", violations[0].Description)
+
+}
@@ -0,0 +1,85 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/grpcapi/pb"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+)
+
+// Create creates a pull request with automatically assigned reviewers
+func (s *Server) Create(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.PullRequestResponse, error) {
+
+	bindedPR := models.PullRequestShort{
+
+		PullRequestID: req.GetPullRequestId(),
+
+		PullRequestName: req.GetPullRequestName(),
+
+		AuthorID: req.GetAuthorId(),
+	}
+
+	if err := s.validator.Validate(&bindedPR); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res, err := pullrequest.Create(ctx, bindedPR)
+
+	if err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	return &pb.PullRequestResponse{Pr: prToPB(res.PullRequest)}, nil
+
+}
+
+// Merge marks a pull request as MERGED
+func (s *Server) Merge(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.PullRequestResponse, error) {
+
+	bindedPR := models.PRMerge{PullRequestID: req.GetPullRequestId()}
+
+	if err := s.validator.Validate(&bindedPR); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res, err := pullrequest.Merge(ctx, bindedPR)
+
+	if err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	return &pb.PullRequestResponse{Pr: prToPB(res.PullRequest)}, nil
+
+}
+
+// Reassign replaces a reviewer with another active team member
+func (s *Server) Reassign(ctx context.Context, req *pb.ReassignRequest) (*pb.ReassignResponse, error) {
+
+	bindedPR := models.PRReassign{PullRequestID: req.GetPullRequestId(), OldReviewerID: req.GetOldReviewerId()}
+
+	if err := s.validator.Validate(&bindedPR); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res, err := pullrequest.Reassign(ctx, bindedPR)
+
+	if err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	return &pb.ReassignResponse{Pr: prToPB(res.PullRequest), ReplacedBy: res.ReplacedBy}, nil
+
+}
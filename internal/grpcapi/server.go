@@ -0,0 +1,54 @@
+package grpcapi
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/grpcapi/pb"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/validation"
+)
+
+// Server implements the gRPC services on top of the team and pullrequest packages
+type Server struct {
+	pb.UnimplementedTeamServiceServer
+
+	pb.UnimplementedUserServiceServer
+
+	pb.UnimplementedPullRequestServiceServer
+
+	validator *validation.Validator // same rules as the HTTP API
+}
+
+// NewServer creates a gRPC server with all services and reflection registered
+func NewServer() *grpc.Server {
+
+	srv := &Server{validator: validation.New()}
+
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler())) // Trace every RPC
+
+	pb.RegisterTeamServiceServer(s, srv)
+
+	pb.RegisterUserServiceServer(s, srv)
+
+	pb.RegisterPullRequestServiceServer(s, srv)
+
+	reflection.Register(s) // Allow grpcurl and similar tools to discover services
+
+	return s
+
+}
+
+// requireField returns a validation failure for an empty required field
+func requireField(field string, value string) error {
+
+	if value == "" {
+
+		return &errs.ValidationFailure{Details: []errs.FieldError{{Field: field, Message: "is required"}}}
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,52 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/grpcapi/pb"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/team"
+)
+
+// AddTeam creates a team with members
+func (s *Server) AddTeam(ctx context.Context, req *pb.Team) (*pb.TeamResponse, error) {
+
+	bindedTeam := teamFromPB(req)
+
+	if err := s.validator.Validate(&bindedTeam); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res, err := team.Add(bindedTeam, ctx)
+
+	if err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	return &pb.TeamResponse{Team: teamToPB(res.Team)}, nil
+
+}
+
+// GetTeam returns a team with members
+func (s *Server) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.Team, error) {
+
+	if err := requireField("team_name", req.GetTeamName()); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res, err := team.Get(req.GetTeamName(), ctx)
+
+	if err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	return teamToPB(res), nil
+
+}
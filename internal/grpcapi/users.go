@@ -0,0 +1,56 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/grpcapi/pb"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/team"
+)
+
+// SetIsActive updates user activity flag
+func (s *Server) SetIsActive(ctx context.Context, req *pb.SetIsActiveRequest) (*pb.UserResponse, error) {
+
+	bindedUser := models.UserActivity{UserID: req.GetUserId(), IsActive: req.GetIsActive()}
+
+	if err := s.validator.Validate(&bindedUser); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res, err := team.SetActive(bindedUser, ctx)
+
+	if err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	return &pb.UserResponse{User: userToPB(res.User)}, nil
+
+}
+
+// GetReview returns pull requests where the user is assigned as reviewer
+func (s *Server) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+
+	if err := requireField("user_id", req.GetUserId()); err != nil {
+
+		return nil, toStatus(err)
+
+	}
+
+	res := pullrequest.GetPR(ctx, req.GetUserId(), models.ReviewFilter{})
+
+	resp := &pb.GetReviewResponse{UserId: res.UserID}
+
+	for _, pr := range res.PullRequests {
+
+		resp.PullRequests = append(resp.PullRequests, prShortToPB(pr))
+
+	}
+
+	return resp, nil
+
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Names of readiness checks
+const (
+	CheckDatabase = "database"
+
+	CheckMigrations = "migrations"
+
+	CheckCache = "cache"
+
+	CheckShutdown = "shutdown"
+)
+
+var (
+	warmedUp atomic.Bool
+
+	warmupErr atomic.Value // error message of a failed warm-up, the cache then fills on demand
+
+	draining atomic.Bool
+)
+
+// MarkWarmedUp records that cache warm-up finished, err is reported but does not block readiness
+func MarkWarmedUp(err error) {
+
+	if err != nil {
+
+		warmupErr.Store(err.Error())
+
+	}
+
+	warmedUp.Store(true)
+
+}
+
+// WarmedUp reports whether cache warm-up finished
+func WarmedUp() bool {
+
+	return warmedUp.Load()
+
+}
+
+// MarkDraining makes the service report not ready while it shuts down
+func MarkDraining() {
+
+	draining.Store(true)
+
+}
+
+// Ready checks every dependency and reports whether the service can take traffic, database checks wait up to timeout
+func Ready(ctx context.Context, timeout time.Duration) (models.Readiness, bool) {
+
+	res := models.Readiness{Status: models.HealthUp, Checks: make(map[string]models.HealthCheck, 4)}
+
+	set := func(name string, err error, message string) {
+
+		check := models.HealthCheck{Status: models.HealthUp, Message: message}
+
+		if err != nil {
+
+			check = models.HealthCheck{Status: models.HealthDown, Message: err.Error()}
+
+			res.Status = models.HealthDown
+
+		}
+
+		res.Checks[name] = check
+
+	}
+
+	set(CheckDatabase, database.PingDB(ctx, timeout), "")
+
+	current, latest, err := database.MigrationVersions(ctx, timeout)
+
+	if err == nil && current < latest {
+
+		err = fmt.Errorf("schema version %d, latest migration %d", current, latest)
+
+	}
+
+	set(CheckMigrations, err, fmt.Sprintf("schema version %d", current))
+
+	err = nil
+
+	message, _ := warmupErr.Load().(string)
+
+	if !WarmedUp() {
+
+		err = fmt.Errorf("warm-up in progress")
+
+	} else if message != "" {
+
+		message = "warm-up failed, filling on demand: " + message
+
+	}
+
+	set(CheckCache, err, message)
+
+	err = nil
+
+	if draining.Load() {
+
+		err = fmt.Errorf("draining")
+
+	}
+
+	set(CheckShutdown, err, "")
+
+	return res, res.Status == models.HealthUp
+
+}
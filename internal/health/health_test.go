@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+func TestReady_ReportsEveryDependency(t *testing.T) {
+
+	ctx := context.Background()
+
+	// Without a database pool the database and migration checks are down
+	res, ok := Ready(ctx, time.Second)
+
+	assert.False(t, ok)
+
+	assert.Equal(t, models.HealthDown, res.Status)
+
+	assert.Equal(t, models.HealthDown, res.Checks[CheckDatabase].Status)
+
+	assert.Equal(t, models.HealthDown, res.Checks[CheckMigrations].Status)
+
+	assert.Equal(t, models.HealthCheck{Status: models.HealthDown, Message: "warm-up in progress"}, res.Checks[CheckCache])
+
+	assert.Equal(t, models.HealthUp, res.Checks[CheckShutdown].Status)
+
+	// A failed warm-up does not block readiness, the cache fills on demand
+	MarkWarmedUp(errors.New("timeout"))
+
+	res, _ = Ready(ctx, time.Second)
+
+	assert.Equal(t, models.HealthUp, res.Checks[CheckCache].Status)
+
+	assert.Contains(t, res.Checks[CheckCache].Message, "timeout")
+
+	MarkDraining()
+
+	res, _ = Ready(ctx, time.Second)
+
+	assert.Equal(t, models.HealthCheck{Status: models.HealthDown, Message: "draining"}, res.Checks[CheckShutdown])
+
+}
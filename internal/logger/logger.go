@@ -1,28 +1,82 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-func Init(level string) {
+// Log output formats
+const (
+	FormatJSON = "json"
 
-	output := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"}
+	FormatConsole = "console" // human readable, for local runs
+)
+
+func init() {
+
+	log.Logger = log.Logger.Hook(traceHook{})
+
+	zerolog.DefaultContextLogger = &log.Logger // Contexts without a request logger use the global one
+
+}
+
+// Init configures the global logger, fields are passed as key/value pairs to every function here
+func Init(format string, level string) {
+
+	if format == FormatConsole {
+
+		output := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"}
+
+		log.Logger = zerolog.New(output).With().Timestamp().Logger().Hook(traceHook{})
+
+	} else {
+
+		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger().Hook(traceHook{})
+
+	}
+
+	if err := SetLevel(level); err != nil {
+
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	}
+
+}
 
-	log.Logger = zerolog.New(output).With().Timestamp().Logger()
+// SetLevel changes the level of every logger, it is safe to call at any time
+func SetLevel(level string) error {
 
 	l, err := zerolog.ParseLevel(level)
 
 	if err != nil {
 
-		l = zerolog.InfoLevel
+		return err
 
 	}
 
 	zerolog.SetGlobalLevel(l)
 
+	return nil
+
+}
+
+// FromContext returns the request logger stored in ctx, or the global logger
+func FromContext(ctx context.Context) *zerolog.Logger {
+
+	return zerolog.Ctx(ctx)
+
+}
+
+// WithFields returns a context whose logger adds the given key/value pairs to every line
+func WithFields(ctx context.Context, fields ...interface{}) context.Context {
+
+	l := FromContext(ctx).With().Fields(fields).Logger()
+
+	return l.WithContext(ctx)
+
 }
 
 func Info(msg string, fields ...interface{}) {
@@ -31,15 +85,29 @@ func Info(msg string, fields ...interface{}) {
 
 }
 
-func Error(err error, msg string) {
+func Error(err error, msg string, fields ...interface{}) {
+
+	log.Error().Err(err).Fields(fields).Msg(msg)
+
+}
+
+// ErrorCtx logs an error with the request fields and trace of ctx
+func ErrorCtx(ctx context.Context, err error, msg string, fields ...interface{}) {
+
+	FromContext(ctx).Error().Ctx(ctx).Err(err).Fields(fields).Msg(msg)
+
+}
+
+// InfoCtx logs a message with the request fields and trace of ctx
+func InfoCtx(ctx context.Context, msg string, fields ...interface{}) {
 
-	log.Error().Err(err).Msg(msg)
+	FromContext(ctx).Info().Ctx(ctx).Fields(fields).Msg(msg)
 
 }
 
-func Fatal(err error, msg string) {
+func Fatal(err error, msg string, fields ...interface{}) {
 
-	log.Fatal().Err(err).Msg(msg)
+	log.Fatal().Err(err).Fields(fields).Msg(msg)
 
 }
 
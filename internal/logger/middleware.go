@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderUserID names the caller, set by the gateway in front of the service
+const HeaderUserID = "X-User-ID"
+
+// Middleware puts a request logger with request_id, route and user into the request context
+// and writes one access line per request, run it after echo's RequestID middleware
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+
+	return func(c echo.Context) error {
+
+		start := time.Now()
+
+		req := c.Request()
+
+		fields := []interface{}{
+
+			"request_id", c.Response().Header().Get(echo.HeaderXRequestID),
+
+			"route", c.Path(),
+
+			"method", req.Method,
+		}
+
+		if user := req.Header.Get(HeaderUserID); user != "" {
+
+			fields = append(fields, "user", user)
+
+		}
+
+		ctx := WithFields(req.Context(), fields...)
+
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+
+		status := c.Response().Status
+
+		if err != nil { // The error handler writes the response later, take its status from the error
+
+			var httpErr *echo.HTTPError
+
+			status = http.StatusInternalServerError
+
+			if errors.As(err, &httpErr) {
+
+				status = httpErr.Code
+
+			}
+
+		}
+
+		event := FromContext(ctx).Info()
+
+		if status >= http.StatusInternalServerError {
+
+			event = FromContext(ctx).Error().Err(err)
+
+		}
+
+		event.Ctx(ctx).
+			Str("uri", req.RequestURI).
+			Int("status", status).
+			Dur("latency", time.Since(start)).
+			Int64("bytes_out", c.Response().Size).
+			Str("remote_ip", c.RealIP()).
+			Msg("request")
+
+		return err
+
+	}
+
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_AddsRequestFields(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	prev := log.Logger
+
+	log.Logger = zerolog.New(&buf)
+
+	defer func() { log.Logger = prev }()
+
+	e := echo.New()
+
+	e.Use(middleware.RequestID(), Middleware)
+
+	e.GET("/team/get", func(c echo.Context) error {
+
+		ErrorCtx(c.Request().Context(), assert.AnError, "query failed")
+
+		return c.NoContent(http.StatusOK)
+
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil)
+
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+
+	req.Header.Set(HeaderUserID, "u1")
+
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+
+	assert.Len(t, lines, 2) // Handler error line and access line
+
+	for _, line := range lines {
+
+		assert.Contains(t, string(line), `"request_id":"req-1"`)
+
+		assert.Contains(t, string(line), `"route":"/team/get"`)
+
+		assert.Contains(t, string(line), `"user":"u1"`)
+
+	}
+
+	assert.Contains(t, string(lines[1]), `"status":200`)
+
+}
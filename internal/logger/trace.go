@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceHook adds the trace and span IDs of the event context, so log lines can be matched to traces
+type traceHook struct{}
+
+func (traceHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+
+	sc := trace.SpanContextFromContext(e.GetCtx())
+
+	if !sc.IsValid() {
+
+		return
+
+	}
+
+	e.Str("trace_id", sc.TraceID().String()).Str("span_id", sc.SpanID().String())
+
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestErrorCtx_AddsTraceIDs(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	prev := log.Logger
+
+	log.Logger = zerolog.New(&buf).Hook(traceHook{})
+
+	defer func() { log.Logger = prev }()
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "reassign")
+
+	defer span.End()
+
+	ErrorCtx(ctx, errors.New("boom"), "query failed")
+
+	assert.Contains(t, buf.String(), `"trace_id":"`+span.SpanContext().TraceID().String()+`"`)
+
+	assert.Contains(t, buf.String(), `"span_id":"`+span.SpanContext().SpanID().String()+`"`)
+
+	buf.Reset()
+
+	ErrorCtx(context.Background(), errors.New("boom"), "no trace")
+
+	assert.NotContains(t, buf.String(), "trace_id")
+
+}
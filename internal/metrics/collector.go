@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+)
+
+var (
+	cacheHitRatioDesc = prometheus.NewDesc("cache_hit_ratio",
+		"Доля попаданий в кэш с момента запуска", []string{"cache"}, nil)
+
+	openReviewsDesc = prometheus.NewDesc("team_open_reviews",
+		"Количество назначенных ревью по открытым PR в разрезе команд ревьюверов", []string{"team"}, nil)
+)
+
+// OpenReviewsSource returns open review counts by team, it is queried on every scrape
+// Left nil until the database is available
+var OpenReviewsSource func(ctx context.Context) (map[string]int, error)
+
+// openReviewsTimeout bounds the scrape-time query so a slow database does not stall /metrics
+const openReviewsTimeout = 2 * time.Second
+
+// collector computes metrics derived at scrape time
+type collector struct{}
+
+func (collector) Describe(ch chan<- *prometheus.Desc) {
+
+	ch <- cacheHitRatioDesc
+
+	ch <- openReviewsDesc
+
+}
+
+func (collector) Collect(ch chan<- prometheus.Metric) {
+
+	misses := counterValues(CacheMisses)
+
+	for name, hits := range counterValues(CacheHits) {
+
+		if total := hits + misses[name]; total > 0 {
+
+			ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, hits/total, name)
+
+		}
+
+	}
+
+	if OpenReviewsSource == nil {
+
+		return
+
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), openReviewsTimeout)
+
+	defer cancel()
+
+	counts, err := OpenReviewsSource(ctx)
+
+	if err != nil {
+
+		logger.Error(err, "failed to collect open reviews")
+
+		return
+
+	}
+
+	for team, count := range counts {
+
+		ch <- prometheus.MustNewConstMetric(openReviewsDesc, prometheus.GaugeValue, float64(count), team)
+
+	}
+
+}
+
+// counterValues reads a single-label counter vector as label value -> count
+func counterValues(vec *prometheus.CounterVec) map[string]float64 {
+
+	res := make(map[string]float64)
+
+	ch := make(chan prometheus.Metric)
+
+	go func() {
+
+		vec.Collect(ch)
+
+		close(ch)
+
+	}()
+
+	for metric := range ch {
+
+		var m dto.Metric
+
+		if err := metric.Write(&m); err != nil || len(m.GetLabel()) == 0 {
+
+			continue
+
+		}
+
+		res[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+
+	}
+
+	return res
+
+}
@@ -9,16 +9,98 @@ var (
 			Help: "Общее количество созданных пользователей",
 		})
 
-	HttpDuration = prometheus.NewHistogram(
+	HttpDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "http_request_duration_seconds",
 			Help:    "Время ответа API",
 			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status"})
+
+	HttpRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Количество запросов к API",
+		}, []string{"route", "method", "status"})
+
+	PRCreatedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pull_requests_created_total",
+			Help: "Количество созданных PR",
+		})
+
+	PRMergedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pull_requests_merged_total",
+			Help: "Количество слитых PR",
+		})
+
+	ReassignmentsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "reviewer_reassignments_total",
+			Help: "Количество переназначений ревьюверов",
+		})
+
+	EscalationsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "reviewer_escalations_total",
+			Help: "Количество автоматических переназначений просроченных ревью",
+		})
+
+	NoCandidateTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "no_candidate_total",
+			Help: "Количество ответов NO_CANDIDATE",
+		}, []string{"operation"})
+
+	TimeToMerge = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "pull_request_time_to_merge_seconds",
+			Help:    "Время от создания PR до слияния",
+			Buckets: prometheus.ExponentialBuckets(60, 4, 10), // from a minute to about half a year
+		})
+
+	RateLimitedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limited_total",
+			Help: "Количество запросов, отклонённых ограничением частоты",
+		}, []string{"budget"})
+
+	ReviewRemindersTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "review_reminders_total",
+			Help: "Количество напоминаний о просроченных ревью",
 		})
+
+	CacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_hits_total",
+			Help: "Количество попаданий в кэш",
+		}, []string{"cache"})
+
+	CacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_misses_total",
+			Help: "Количество промахов кэша",
+		}, []string{"cache"})
+
+	CacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_evictions_total",
+			Help: "Количество вытеснений из кэша по размеру или TTL",
+		}, []string{"cache"})
+
+	CacheSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_size",
+			Help: "Текущее количество записей в кэше",
+		}, []string{"cache"})
 )
 
 func Init() {
 	prometheus.MustRegister(
-		UsersCreatedTotal, HttpDuration,
+		UsersCreatedTotal, HttpDuration, HttpRequests,
+		PRCreatedTotal, PRMergedTotal, ReassignmentsTotal, EscalationsTotal, NoCandidateTotal, TimeToMerge, RateLimitedTotal, ReviewRemindersTotal,
+		CacheHits, CacheMisses, CacheEvictions, CacheSize,
+		collector{},
 	)
 }
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// unmatchedRoute labels requests without a route so unknown paths cannot blow up label cardinality
+const unmatchedRoute = "unmatched"
+
+// Middleware records duration and count of every HTTP request by route template, method and status
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+
+	return func(c echo.Context) error {
+
+		start := time.Now()
+
+		err := next(c)
+
+		status := c.Response().Status
+
+		if err != nil { // The error handler writes the response later, take its status from the error
+
+			var httpErr *echo.HTTPError
+
+			status = http.StatusInternalServerError
+
+			if errors.As(err, &httpErr) {
+
+				status = httpErr.Code
+
+			}
+
+		}
+
+		route := c.Path()
+
+		if route == "" || status == http.StatusNotFound && route == "/*" {
+
+			route = unmatchedRoute
+
+		}
+
+		labels := []string{route, c.Request().Method, strconv.Itoa(status)}
+
+		HttpDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		HttpRequests.WithLabelValues(labels...).Inc()
+
+		return err
+
+	}
+
+}
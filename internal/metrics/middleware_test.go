@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_LabelsByRoute(t *testing.T) {
+
+	e := echo.New()
+
+	e.Use(Middleware)
+
+	e.GET("/team/get", func(c echo.Context) error { return c.JSON(http.StatusOK, "ok") })
+
+	e.POST("/pullRequest/create", func(c echo.Context) error { return echo.NewHTTPError(http.StatusConflict) })
+
+	for _, req := range []*http.Request{
+
+		httptest.NewRequest(http.MethodGet, "/team/get?team_name=a", nil),
+
+		httptest.NewRequest(http.MethodGet, "/team/get?team_name=b", nil),
+
+		httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil),
+
+		httptest.NewRequest(http.MethodGet, "/no/such/path", nil),
+	} {
+
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(HttpRequests.WithLabelValues("/team/get", "GET", "200")))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(HttpRequests.WithLabelValues("/pullRequest/create", "POST", "409")))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(HttpRequests.WithLabelValues(unmatchedRoute, "GET", "404")))
+
+}
+
+func TestCollector_DerivedMetrics(t *testing.T) {
+
+	CacheHits.WithLabelValues("teams").Add(3)
+
+	CacheMisses.WithLabelValues("teams").Add(1)
+
+	OpenReviewsSource = func(context.Context) (map[string]int, error) {
+
+		return map[string]int{"backend": 4}, nil
+
+	}
+
+	defer func() { OpenReviewsSource = nil }()
+
+	registry := prometheus.NewRegistry()
+
+	registry.MustRegister(collector{})
+
+	families, err := registry.Gather()
+
+	assert.NoError(t, err)
+
+	values := map[string]float64{}
+
+	for _, family := range families {
+
+		for _, metric := range family.GetMetric() {
+
+			values[family.GetName()+"/"+metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+
+		}
+
+	}
+
+	assert.Equal(t, map[string]float64{"cache_hit_ratio/teams": 0.75, "team_open_reviews/backend": 4}, values)
+
+}
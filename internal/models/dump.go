@@ -0,0 +1,86 @@
+package models
+
+// DumpVersion is the current version of the export format
+// Version 2 added team policies and pull request history, version 1 dumps are imported without them
+const DumpVersion = 2
+
+// Dump is a full export of teams, users, team policies and pull requests
+// Used by /admin/export and /admin/import
+type Dump struct {
+	Version      int               `json:"version"`
+	ExportedAt   string            `json:"exported_at,omitempty"`
+	Teams        []DumpTeam        `json:"teams"`
+	Users        []User            `json:"users"`
+	TeamPolicies []TeamPolicy      `json:"team_policies,omitempty"` // missing in version 1
+	PullRequests []DumpPullRequest `json:"pull_requests"`
+}
+
+// DumpTeam is an exported team, members are exported as users
+type DumpTeam struct {
+	TeamName string `json:"team_name"`
+}
+
+// DumpPullRequest is an exported pull request with its full reviewer history
+type DumpPullRequest struct {
+	PullRequestID   string         `json:"pull_request_id"`
+	PullRequestName string         `json:"pull_request_name"`
+	AuthorID        string         `json:"author_id"`
+	Status          string         `json:"status"` // OPEN, MERGED
+	CreatedAt       string         `json:"createdAt,omitempty"`
+	MergedAt        string         `json:"mergedAt,omitempty"`
+	Priority        string         `json:"priority,omitempty"` // missing in dumps made before priorities, imported as normal
+	Labels          []string       `json:"labels,omitempty"`
+	Reviewers       []DumpReviewer `json:"reviewers"`
+
+	History []PRHistoryEntry `json:"history,omitempty"` // reviewer changes oldest first, missing in version 1
+}
+
+// DumpReviewer is a single pr_reviewers row, including unassigned reviewers
+type DumpReviewer struct {
+	UserID     string `json:"user_id"`
+	Slot       int    `json:"slot"`
+	State      string `json:"state"` // ASSIGNED, UNASSIGNED
+	AssignedAt string `json:"assigned_at"`
+	AssignedBy string `json:"assigned_by"`
+}
+
+// DumpRecord types
+const (
+	RecordHeader = "header"
+
+	RecordTeam = "team"
+
+	RecordUser = "user"
+
+	RecordTeamPolicy = "team_policy"
+
+	RecordPullRequest = "pull_request"
+)
+
+// DumpRecord is a single line of an NDJSON dump
+// The first record is a header carrying the version, then one record per entity
+type DumpRecord struct {
+	Type        string           `json:"type"` // header, team, user, team_policy, pull_request
+	Version     int              `json:"version,omitempty"`
+	ExportedAt  string           `json:"exported_at,omitempty"`
+	Team        *DumpTeam        `json:"team,omitempty"`
+	User        *User            `json:"user,omitempty"`
+	TeamPolicy  *TeamPolicy      `json:"team_policy,omitempty"`
+	PullRequest *DumpPullRequest `json:"pull_request,omitempty"`
+}
+
+// ImportCounts is the outcome of importing one kind of entity
+type ImportCounts struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
+
+// ImportResult is the response of a successful import
+type ImportResult struct {
+	Policy       string       `json:"policy"`
+	Teams        ImportCounts `json:"teams"`
+	Users        ImportCounts `json:"users"`
+	TeamPolicies ImportCounts `json:"team_policies"`
+	PullRequests ImportCounts `json:"pull_requests"`
+}
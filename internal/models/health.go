@@ -0,0 +1,20 @@
+package models
+
+// Health check statuses
+const (
+	HealthUp = "up"
+
+	HealthDown = "down"
+)
+
+// HealthCheck is the state of a single dependency
+type HealthCheck struct {
+	Status  string `json:"status"` // up, down
+	Message string `json:"message,omitempty"`
+}
+
+// Readiness is the response of the readiness probe with a breakdown per dependency
+type Readiness struct {
+	Status string                 `json:"status"` // up only if every check is up
+	Checks map[string]HealthCheck `json:"checks"`
+}
@@ -10,22 +10,39 @@ type PullRequest struct {
 	AssignedReviewers []string `json:"assigned_reviewers"`
 	CreatedAt         string   `json:"createdAt,omitempty"`
 	MergedAt          string   `json:"mergedAt,omitempty"`
+	Priority          string   `json:"priority,omitempty"` // low, normal, high, urgent
+	Labels            []string `json:"labels,omitempty"`
 }
 
 // PullRequestShort represents a simplified view of a Pull Request
-// Used for
+// Used for create requests and review lists
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"` // OPEN, MERGED
+	PullRequestID   string   `json:"pull_request_id" validate:"required,notblank,max=255"`
+	PullRequestName string   `json:"pull_request_name" validate:"required,notblank,max=255"`
+	AuthorID        string   `json:"author_id" validate:"required,notblank,max=255"`
+	Status          string   `json:"status"`                                                               // OPEN, MERGED
+	Priority        string   `json:"priority,omitempty" validate:"omitempty,oneof=low normal high urgent"` // normal when empty
+	Labels          []string `json:"labels,omitempty" validate:"max=20,dive,required,notblank,max=50"`
+}
+
+// PRMerge represents the request for merging a pull request
+// Used in the merge operation
+type PRMerge struct {
+	PullRequestID string `json:"pull_request_id" validate:"required,notblank,max=255"`
 }
 
 // PRReassign represents the request for reassigning a reviewer
 // Used in the reassign reviewer operation
 type PRReassign struct {
-	PullRequestID string `json:"pull_request_id"`
-	OldReviewerID string `json:"old_reviewer_id"`
+	PullRequestID string `json:"pull_request_id" validate:"required,notblank,max=255"`
+	OldReviewerID string `json:"old_reviewer_id" validate:"required,notblank,max=255"`
+}
+
+// PRDecline is a reviewer turning down their own assignment
+type PRDecline struct {
+	PullRequestID string `json:"pull_request_id" validate:"required,notblank,max=255"`
+	UserID        string `json:"user_id" validate:"required,notblank,max=255"`
+	Reason        string `json:"reason" validate:"required,notblank,max=1000"`
 }
 
 // PRReassignResponse represents the response after successfully reassigning a reviewer
@@ -35,6 +52,56 @@ type PRReassignResponse struct {
 	ReplacedBy  string      `json:"replaced_by"`
 }
 
+// PRHistoryEntry is one recorded change of the reviewers of a pull request
+type PRHistoryEntry struct {
+	Action     string `json:"action"`  // reassigned, escalated, declined
+	UserID     string `json:"user_id"` // reviewer taken off the pull request
+	ReplacedBy string `json:"replaced_by,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	At         string `json:"at,omitempty"`
+}
+
+// PRHistory lists the reviewer changes of a pull request, oldest first
+type PRHistory struct {
+	PullRequestID string           `json:"pull_request_id"`
+	History       []PRHistoryEntry `json:"history"`
+}
+
 type PRResponse struct {
 	PullRequest PullRequest `json:"pr"`
 }
+
+// PRBatchCreate is a batch of pull requests to create
+// Atomic writes all of them in one transaction or none, otherwise every item is written on its own
+type PRBatchCreate struct {
+	PullRequests []PullRequestShort `json:"pull_requests" validate:"required,min=1,max=1000,dive"`
+	Atomic       bool               `json:"atomic"`
+}
+
+// PRBatchMerge is a batch of pull requests to merge, Atomic as in PRBatchCreate
+type PRBatchMerge struct {
+	PullRequests []PRMerge `json:"pull_requests" validate:"required,min=1,max=1000,dive"`
+	Atomic       bool      `json:"atomic"`
+}
+
+// BatchItemResult is the outcome of one batch item
+type BatchItemResult struct {
+	PullRequestID string          `json:"pull_request_id"`
+	Status        string          `json:"status"` // ok, failed, aborted (valid, but its atomic batch failed)
+	PullRequest   *PullRequest    `json:"pr,omitempty"`
+	Error         *BatchItemError `json:"error,omitempty"`
+}
+
+// BatchItemError carries the errs code of a failed item
+type BatchItemError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PRBatchResponse lists results in request order
+type PRBatchResponse struct {
+	Atomic    bool              `json:"atomic"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// TeamPolicy holds per-team review settings, zero values fall back to the service defaults
+type TeamPolicy struct {
+	TeamName       string `json:"team_name" validate:"required,notblank,max=255"`
+	ReviewSLAHours int    `json:"review_sla_hours" validate:"gte=0,lte=8760"` // hours a reviewer has before the review is stale
+
+	// Reviews still unanswered after EscalateAfterHours are reassigned automatically,
+	// at most MaxEscalations times per pull request, zero in either disables escalation
+	EscalateAfterHours int `json:"escalate_after_hours" validate:"gte=0,lte=8760"`
+	MaxEscalations     int `json:"max_escalations" validate:"gte=0,lte=100"`
+
+	// Reviewer requirements checked on every assignment: at least MinSeniorReviewers senior reviewers,
+	// and for each pull request label listed in SkillLabels a reviewer with the skill of the same name
+	MinSeniorReviewers int      `json:"min_senior_reviewers" validate:"gte=0,lte=10"`
+	SkillLabels        []string `json:"skill_labels,omitempty" validate:"max=50,dive,required,notblank,max=50"`
+}
+
+// StaleReview is an assigned review of an OPEN pull request older than its team SLA
+// For escalations SLASeconds is the escalation threshold of the team
+type StaleReview struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	ReviewerID      string `json:"reviewer_id"`
+	TeamName        string `json:"team_name"`
+	AgeSeconds      int64  `json:"age_seconds"` // time since the reviewer was assigned
+	SLASeconds      int64  `json:"sla_seconds"`
+}
+
+// StaleReviewList is a wrapper for stale review responses, oldest first
+type StaleReviewList struct {
+	Reviews []StaleReview `json:"reviews"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Stats is an aggregated snapshot of teams, users and review load
+type Stats struct {
+	Teams        int             `json:"teams"`
+	Users        int             `json:"users"`
+	ActiveUsers  int             `json:"active_users"`
+	PullRequests map[string]int  `json:"pull_requests"` // count by status
+	Reviewers    []ReviewerStats `json:"reviewers"`
+}
+
+// ReviewerStats is the review load of a single user
+type ReviewerStats struct {
+	UserID       string `json:"user_id"`
+	OpenReviews  int    `json:"open_reviews"`
+	TotalReviews int    `json:"total_reviews"`
+
+	Declined    int     `json:"declined"`     // assignments the user declined
+	DeclineRate float64 `json:"decline_rate"` // declined share of every assignment the user ever got
+}
+
+// TeamList is a wrapper for team list responses
+type TeamList struct {
+	Teams []Team `json:"teams"`
+}
+
+// PullRequestList is a wrapper for pull request list responses
+type PullRequestList struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+}
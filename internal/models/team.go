@@ -2,15 +2,17 @@ package models
 
 // Team represents a team
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName string       `json:"team_name" validate:"required,notblank,max=255"`
+	Members  []TeamMember `json:"members" validate:"required,min=1,dive"`
 }
 
 // TeamMember represents a user within a team context
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id" validate:"required,notblank,max=255"`
+	Username string   `json:"username" validate:"required,notblank,max=255"`
+	IsActive bool     `json:"is_active"`
+	Level    string   `json:"level,omitempty" validate:"omitempty,oneof=junior middle senior"` // middle when empty
+	Skills   []string `json:"skills,omitempty" validate:"max=20,dive,required,notblank,max=50"`
 }
 
 // TeamResponse is a wrapper for team-related API responses
@@ -24,5 +26,7 @@ func UserToTM(user User) TeamMember {
 		UserID:   user.UserID,
 		Username: user.Username,
 		IsActive: user.IsActive,
+		Level:    user.Level,
+		Skills:   user.Skills,
 	}
 }
@@ -1,25 +1,50 @@
 package models
 
+// User levels, reviewer requirements of team policies count seniors
+const (
+	LevelJunior = "junior"
+
+	LevelMiddle = "middle" // given to users added without a level
+
+	LevelSenior = "senior"
+)
+
 // User represents a full user entity
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName string   `json:"team_name"`
+	IsActive bool     `json:"is_active"`
+	Level    string   `json:"level,omitempty"` // junior, middle, senior
+	Skills   []string `json:"skills,omitempty"`
 }
 
 // UserActivity represents a request to update user activation status
 // Used in the setActivity operation
 type UserActivity struct {
-	UserID   string `json:"user_id"`
+	UserID   string `json:"user_id" validate:"required,notblank,max=255"`
 	IsActive bool   `json:"is_active"`
 }
 
+// UserProfile replaces the level and skills of a user
+// Used in the setProfile operation
+type UserProfile struct {
+	UserID string   `json:"user_id" validate:"required,notblank,max=255"`
+	Level  string   `json:"level" validate:"required,oneof=junior middle senior"`
+	Skills []string `json:"skills" validate:"max=20,dive,required,notblank,max=50"`
+}
+
 // UserResponse is a wrapper for user-related API responses
 type UserResponse struct {
 	User User `json:"user"`
 }
 
+// ReviewFilter narrows a review queue, empty fields match everything
+type ReviewFilter struct {
+	Status string // OPEN, MERGED
+	Label  string
+}
+
 // UserRequests represents a collection of pull requests assigned to a user for review
 // Used in the getReview operation
 type UserRequests struct {
@@ -0,0 +1,297 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// Batch item statuses
+const (
+	BatchOK = "ok"
+
+	BatchFailed = "failed"
+
+	BatchAborted = "aborted" // the item was valid, but another item of its atomic batch failed
+)
+
+// CreateBatch creates pull requests, spreading reviews over the team so the batch does not land on the same reviewers
+func CreateBatch(ctx context.Context, batch models.PRBatchCreate) models.PRBatchResponse {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.CreateBatch", attribute.Int("batch.size", len(batch.PullRequests)), attribute.Bool("batch.atomic", batch.Atomic))
+
+	defer span.End()
+
+	res := newBatchResponse(batch.Atomic, len(batch.PullRequests))
+
+	pending := make([]*models.PullRequest, len(batch.PullRequests)) // prepared items of an atomic batch
+
+	load := make(map[string]int) // reviews given in this batch
+
+	seen := make(map[string]bool, len(batch.PullRequests))
+
+	for i, item := range batch.PullRequests {
+
+		res.Results[i].PullRequestID = item.PullRequestID
+
+		if seen[item.PullRequestID] {
+
+			fail(&res.Results[i], errs.ErrPRExists)
+
+			continue
+
+		}
+
+		seen[item.PullRequestID] = true
+
+		req, err := newPR(ctx, item, load)
+
+		if err != nil {
+
+			fail(&res.Results[i], err)
+
+			continue
+
+		}
+
+		if batch.Atomic {
+
+			pending[i] = &req
+
+			continue
+
+		}
+
+		if err := repository.Default.CreatePRs(ctx, []models.PullRequest{req}); err != nil {
+
+			if !errors.Is(err, errs.ErrPRExists) {
+
+				err = errs.ErrDatabase
+
+			}
+
+			fail(&res.Results[i], err)
+
+			continue
+
+		}
+
+		created(req)
+
+		succeed(&res.Results[i], req)
+
+	}
+
+	if batch.Atomic {
+
+		commitBatch(ctx, &res, pending, repository.Default.CreatePRs, created)
+
+	}
+
+	return count(res)
+
+}
+
+// MergeBatch merges pull requests, already merged ones succeed without a write
+func MergeBatch(ctx context.Context, batch models.PRBatchMerge) models.PRBatchResponse {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.MergeBatch", attribute.Int("batch.size", len(batch.PullRequests)), attribute.Bool("batch.atomic", batch.Atomic))
+
+	defer span.End()
+
+	if !batch.Atomic {
+
+		res := newBatchResponse(false, len(batch.PullRequests))
+
+		for i, item := range batch.PullRequests {
+
+			res.Results[i].PullRequestID = item.PullRequestID
+
+			merge, err := Merge(ctx, item)
+
+			if err != nil {
+
+				fail(&res.Results[i], err)
+
+				continue
+
+			}
+
+			succeed(&res.Results[i], merge.PullRequest)
+
+		}
+
+		return count(res)
+
+	}
+
+	res := newBatchResponse(true, len(batch.PullRequests))
+
+	pending := make([]*models.PullRequest, len(batch.PullRequests))
+
+	for i, item := range batch.PullRequests {
+
+		res.Results[i].PullRequestID = item.PullRequestID
+
+		req, err, ok := repository.Default.GetPR(ctx, item.PullRequestID)
+
+		if err != nil {
+
+			fail(&res.Results[i], errs.ErrDatabase)
+
+			continue
+
+		}
+
+		if !ok {
+
+			fail(&res.Results[i], errs.ErrNotFound)
+
+			continue
+
+		}
+
+		if req.Status == MergeStatus {
+
+			succeed(&res.Results[i], req)
+
+			continue
+
+		}
+
+		req.Status = MergeStatus
+
+		req.MergedAt = time.Now().UTC().Format(time.RFC3339)
+
+		pending[i] = &req
+
+	}
+
+	commitBatch(ctx, &res, pending, repository.Default.SetPRs, merged)
+
+	return count(res)
+
+}
+
+// commitBatch writes the pending items of an atomic batch in one transaction
+// If any item failed, nothing is written and the pending items are aborted
+// A pull request created concurrently fails its own item with PR_EXISTS and aborts the rest
+func commitBatch(ctx context.Context, res *models.PRBatchResponse, pending []*models.PullRequest, write func(context.Context, []models.PullRequest) error, done func(models.PullRequest)) {
+
+	failed := false
+
+	var prs []models.PullRequest
+
+	for i, pr := range pending {
+
+		if res.Results[i].Status == BatchFailed {
+
+			failed = true
+
+		}
+
+		if pr != nil {
+
+			prs = append(prs, *pr)
+
+		}
+
+	}
+
+	var err error
+
+	if !failed && len(prs) != 0 {
+
+		err = write(ctx, prs)
+
+	}
+
+	var exists *errs.PRExistsFailure
+
+	errors.As(err, &exists)
+
+	for i, pr := range pending {
+
+		switch {
+
+		case pr == nil:
+
+		case failed:
+
+			res.Results[i].Status = BatchAborted
+
+		case exists != nil && exists.PullRequestID == pr.PullRequestID:
+
+			fail(&res.Results[i], errs.ErrPRExists)
+
+		case exists != nil:
+
+			res.Results[i].Status = BatchAborted
+
+		case err != nil:
+
+			fail(&res.Results[i], errs.ErrDatabase)
+
+		default:
+
+			done(*pr)
+
+			succeed(&res.Results[i], *pr)
+
+		}
+
+	}
+
+}
+
+func newBatchResponse(atomic bool, size int) models.PRBatchResponse {
+
+	return models.PRBatchResponse{Atomic: atomic, Results: make([]models.BatchItemResult, size)}
+
+}
+
+func succeed(item *models.BatchItemResult, pr models.PullRequest) {
+
+	item.Status = BatchOK
+
+	item.PullRequest = &pr
+
+}
+
+func fail(item *models.BatchItemResult, err error) {
+
+	item.Status = BatchFailed
+
+	item.Error = &models.BatchItemError{Code: string(errs.CodeOf(err)), Message: err.Error()}
+
+}
+
+// count fills the totals of the response
+func count(res models.PRBatchResponse) models.PRBatchResponse {
+
+	for _, item := range res.Results {
+
+		switch item.Status {
+
+		case BatchOK:
+
+			res.Succeeded++
+
+		case BatchFailed:
+
+			res.Failed++
+
+		}
+
+	}
+
+	return res
+
+}
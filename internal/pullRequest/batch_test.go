@@ -0,0 +1,284 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+)
+
+// memRepo keeps one team in memory, methods the batch does not use are left to the nil interface
+type memRepo struct {
+	repository.Repository
+
+	team models.Team
+
+	policy models.TeamPolicy
+
+	prs map[string]models.PullRequest
+
+	history map[string][]models.PRHistoryEntry
+
+	failWrites bool
+
+	concurrent map[string]bool // created by another instance after newPR checked, GetPR does not see them
+}
+
+func (m *memRepo) GetTeam(_ context.Context, _ string) (models.Team, error, bool) {
+
+	return m.team, nil, true
+
+}
+
+func (m *memRepo) GetTeamPolicy(_ context.Context, _ string) (models.TeamPolicy, error, bool) {
+
+	return m.policy, nil, true
+
+}
+
+func (m *memRepo) GetUser(_ context.Context, userID string) (models.User, error, bool) {
+
+	for _, member := range m.team.Members {
+
+		if member.UserID == userID {
+
+			return models.User{UserID: userID, TeamName: m.team.TeamName, IsActive: member.IsActive}, nil, true
+
+		}
+
+	}
+
+	return models.User{}, nil, false
+
+}
+
+func (m *memRepo) GetPR(_ context.Context, prID string) (models.PullRequest, error, bool) {
+
+	pr, ok := m.prs[prID]
+
+	return pr, nil, ok
+
+}
+
+func (m *memRepo) SetPR(ctx context.Context, pr models.PullRequest) error {
+
+	return m.SetPRs(ctx, []models.PullRequest{pr})
+
+}
+
+func (m *memRepo) SetPRs(_ context.Context, prs []models.PullRequest) error {
+
+	if m.failWrites {
+
+		return errors.New("write failed")
+
+	}
+
+	for _, pr := range prs {
+
+		m.prs[pr.PullRequestID] = pr
+
+	}
+
+	return nil
+
+}
+
+func (m *memRepo) CreatePRs(ctx context.Context, prs []models.PullRequest) error {
+
+	for _, pr := range prs {
+
+		if _, ok := m.prs[pr.PullRequestID]; ok || m.concurrent[pr.PullRequestID] {
+
+			return &errs.PRExistsFailure{PullRequestID: pr.PullRequestID}
+
+		}
+
+	}
+
+	return m.SetPRs(ctx, prs)
+
+}
+
+func (m *memRepo) SetPRWithHistory(ctx context.Context, pr models.PullRequest, entry models.PRHistoryEntry) error {
+
+	if err := m.SetPRs(ctx, []models.PullRequest{pr}); err != nil {
+
+		return err
+
+	}
+
+	m.history[pr.PullRequestID] = append(m.history[pr.PullRequestID], entry)
+
+	return nil
+
+}
+
+// RecentPairs counts current reviewers of the stored pull requests, the window is ignored
+func (m *memRepo) RecentPairs(_ context.Context, authorID string, _ time.Duration) (map[string]int, error) {
+
+	pairs := make(map[string]int)
+
+	for _, pr := range m.prs {
+
+		if pr.AuthorID == authorID {
+
+			for _, id := range pr.AssignedReviewers {
+
+				pairs[id]++
+
+			}
+
+		}
+
+	}
+
+	return pairs, nil
+
+}
+
+func (m *memRepo) GetPRHistory(_ context.Context, prID string) ([]models.PRHistoryEntry, error) {
+
+	return m.history[prID], nil
+
+}
+
+func useMemRepo(t *testing.T) *memRepo {
+
+	repo := &memRepo{prs: make(map[string]models.PullRequest), history: make(map[string][]models.PRHistoryEntry), team: models.Team{TeamName: "backend", Members: []models.TeamMember{
+
+		{UserID: "u1", IsActive: true}, {UserID: "u2", IsActive: true}, {UserID: "u3", IsActive: true}, {UserID: "u4", IsActive: true},
+	}}}
+
+	prev := repository.Default
+
+	repository.Default = repo
+
+	Init(config.Assignment{Reviewers: 2, Strategy: config.StrategyOrdered})
+
+	t.Cleanup(func() { repository.Default = prev })
+
+	return repo
+
+}
+
+func TestCreateBatch_BalancesReviewers(t *testing.T) {
+
+	useMemRepo(t)
+
+	batch := models.PRBatchCreate{}
+
+	for _, id := range []string{"pr1", "pr2", "pr3", "pr4"} {
+
+		batch.PullRequests = append(batch.PullRequests, models.PullRequestShort{PullRequestID: id, PullRequestName: id, AuthorID: "u1"})
+
+	}
+
+	res := CreateBatch(context.Background(), batch)
+
+	assert.Equal(t, 4, res.Succeeded)
+
+	load := map[string]int{}
+
+	for _, item := range res.Results {
+
+		for _, id := range item.PullRequest.AssignedReviewers {
+
+			load[id]++
+
+		}
+
+	}
+
+	assert.Equal(t, map[string]int{"u2": 3, "u3": 3, "u4": 2}, load) // Without balancing u2 and u3 would get all 8
+
+}
+
+func TestCreateBatch_AtomicAbortsOnFailure(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	res := CreateBatch(context.Background(), models.PRBatchCreate{Atomic: true, PullRequests: []models.PullRequestShort{
+
+		{PullRequestID: "pr1", PullRequestName: "a", AuthorID: "u1"},
+
+		{PullRequestID: "pr2", PullRequestName: "b", AuthorID: "ghost"},
+
+		{PullRequestID: "pr1", PullRequestName: "c", AuthorID: "u1"},
+	}})
+
+	assert.Equal(t, BatchAborted, res.Results[0].Status)
+
+	assert.Equal(t, string(errs.CodeNotFound), res.Results[1].Error.Code)
+
+	assert.Equal(t, string(errs.CodePRExists), res.Results[2].Error.Code)
+
+	assert.Equal(t, 0, res.Succeeded)
+
+	assert.Equal(t, 2, res.Failed)
+
+	assert.Empty(t, repo.prs)
+
+}
+
+func TestMergeBatch(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.prs["pr1"] = models.PullRequest{PullRequestID: "pr1", Status: OpenStatus}
+
+	repo.prs["pr2"] = models.PullRequest{PullRequestID: "pr2", Status: MergeStatus}
+
+	batch := models.PRBatchMerge{Atomic: true, PullRequests: []models.PRMerge{{PullRequestID: "pr1"}, {PullRequestID: "pr2"}}}
+
+	repo.failWrites = true
+
+	res := MergeBatch(context.Background(), batch)
+
+	assert.Equal(t, string(errs.CodeDatabaseError), res.Results[0].Error.Code)
+
+	assert.Equal(t, BatchOK, res.Results[1].Status) // Already merged, nothing to write
+
+	repo.failWrites = false
+
+	batch.Atomic = false
+
+	res = MergeBatch(context.Background(), batch)
+
+	assert.Equal(t, 2, res.Succeeded)
+
+	assert.Equal(t, MergeStatus, repo.prs["pr1"].Status)
+
+}
+
+func TestCreate_ConcurrentCreateIsNotOverwritten(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.concurrent = map[string]bool{"pr2": true}
+
+	_, err := Create(context.Background(), models.PullRequestShort{PullRequestID: "pr2", PullRequestName: "b", AuthorID: "u1"})
+
+	assert.ErrorIs(t, err, errs.ErrPRExists)
+
+	res := CreateBatch(context.Background(), models.PRBatchCreate{Atomic: true, PullRequests: []models.PullRequestShort{
+
+		{PullRequestID: "pr1", PullRequestName: "a", AuthorID: "u1"},
+
+		{PullRequestID: "pr2", PullRequestName: "b", AuthorID: "u1"},
+	}})
+
+	assert.Equal(t, BatchAborted, res.Results[0].Status)
+
+	assert.Equal(t, string(errs.CodePRExists), res.Results[1].Error.Code)
+
+	assert.Empty(t, repo.prs)
+
+}
@@ -2,30 +2,87 @@ package pullrequest
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync/atomic"
 	"time"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
 // PR status constants
 var MergeStatus = "MERGED"
 var OpenStatus = "OPEN"
 
+// PR priorities, review queues put higher ones first
+const (
+	PriorityLow = "low"
+
+	PriorityNormal = "normal" // used when a pull request is created without one
+
+	PriorityHigh = "high"
+
+	PriorityUrgent = "urgent"
+)
+
+// PR history actions
+const (
+	HistoryReassigned = "reassigned" // reviewer replaced through the API
+
+	HistoryEscalated = "escalated" // reviewer replaced by the SLA scheduler, counted against max_escalations
+
+	HistoryDeclined = "declined" // reviewer turned the review down, never picked for this pull request again
+)
+
+// assignment holds the reviewer defaults, replaced as a whole by Init so a request never sees half of a reload
+var assignment atomic.Pointer[config.Assignment]
+
+func init() {
+
+	Init(config.Default().Assignment)
+
+}
+
+// Init sets the reviewer assignment defaults, it is safe to call while requests are served
+func Init(cfg config.Assignment) {
+
+	assignment.Store(&cfg)
+
+}
+
 // Create creates a new pull request with automatically assigned reviewers
 func Create(ctx context.Context, bindedPR models.PullRequestShort) (models.PRResponse, error) {
 
-	_, ok := cache.PRcache.Get(bindedPR.PullRequestID)
+	ctx, span := tracing.Start(ctx, "pullrequest.Create", attribute.String("pull_request.id", bindedPR.PullRequestID), attribute.String("author.id", bindedPR.AuthorID))
 
-	if ok {
+	defer span.End()
 
-		return models.PRResponse{}, errs.ErrPRExists
+	req, err := newPR(ctx, bindedPR, nil)
+
+	if err != nil {
+
+		return models.PRResponse{}, err
 
 	}
 
-	_, err, ok := database.GetPRFromDB(ctx, bindedPR.PullRequestID)
+	err = repository.Default.CreatePRs(ctx, []models.PullRequest{req}) // The written PR is evicted from the cache, the next read loads it
+
+	if errors.Is(err, errs.ErrPRExists) { // Created by a concurrent request since the check in newPR
+
+		return models.PRResponse{}, errs.ErrPRExists
+
+	}
 
 	if err != nil {
 
@@ -33,37 +90,81 @@ func Create(ctx context.Context, bindedPR models.PullRequestShort) (models.PRRes
 
 	}
 
+	created(req)
+
+	return models.PRResponse{PullRequest: req}, nil
+
+}
+
+// newPR checks the pull request can be created and picks its reviewers without writing it
+// load counts reviews already given in the current batch, nil outside batches
+func newPR(ctx context.Context, bindedPR models.PullRequestShort, load map[string]int) (models.PullRequest, error) {
+
+	_, err, ok := repository.Default.GetPR(ctx, bindedPR.PullRequestID)
+
+	if err != nil {
+
+		return models.PullRequest{}, errs.ErrDatabase
+
+	}
+
 	if ok {
 
-		return models.PRResponse{}, errs.ErrPRExists
+		return models.PullRequest{}, errs.ErrPRExists
 
 	}
 
-	iUser, ok := cache.UserCache.Get(bindedPR.AuthorID)
+	author, err, ok := repository.Default.GetUser(ctx, bindedPR.AuthorID)
+
+	if err != nil {
+
+		return models.PullRequest{}, errs.ErrDatabase
+
+	}
 
 	if !ok {
 
-		iUser, err, ok = database.GetUserFromDB(ctx, bindedPR.AuthorID)
+		return models.PullRequest{}, errs.ErrNotFound
 
-		if err != nil {
+	}
 
-			return models.PRResponse{}, errs.ErrDatabase
+	reqTeam, err, ok := repository.Default.GetTeam(ctx, author.TeamName)
 
-		}
+	if err != nil || !ok {
+
+		return models.PullRequest{}, errs.ErrDatabase
 
-		if !ok {
+	}
 
-			return models.PRResponse{}, errs.ErrNotFound
+	policy, err, _ := repository.Default.GetTeamPolicy(ctx, author.TeamName) // Without a policy there are no rules
 
-		}
+	if err != nil {
 
-		cache.UserCache.Set(bindedPR.AuthorID, iUser.(models.User))
+		return models.PullRequest{}, errs.ErrDatabase
 
 	}
 
-	author := iUser.(models.User)
+	pairs, err := recentPairs(ctx, author.UserID)
+
+	if err != nil {
+
+		return models.PullRequest{}, errs.ErrDatabase
+
+	}
+
+	labels := uniqueLabels(bindedPR.Labels)
+
+	reviewers, ok := pickReviewers(reqTeam.Members, author.UserID, load, pairs, rulesFor(policy, labels))
+
+	if !ok {
+
+		metrics.NoCandidateTotal.WithLabelValues("create").Inc()
 
-	req := models.PullRequest{
+		return models.PullRequest{}, errs.ErrNoCandidate
+
+	}
+
+	return models.PullRequest{
 
 		PullRequestID: bindedPR.PullRequestID,
 
@@ -73,95 +174,164 @@ func Create(ctx context.Context, bindedPR models.PullRequestShort) (models.PRRes
 
 		Status: OpenStatus,
 
-		AssignedReviewers: []string{},
+		AssignedReviewers: reviewers,
 
 		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+
+		Priority: priorityOrDefault(bindedPR.Priority),
+
+		Labels: labels,
+	}, nil
+
+}
+
+func priorityOrDefault(priority string) string {
+
+	if priority == "" {
+
+		return PriorityNormal
+
 	}
 
-	iTeam, ok := cache.TeamCache.Get(author.TeamName)
+	return priority
 
-	if !ok {
+}
+
+// uniqueLabels drops repeated labels keeping the first occurrence
+func uniqueLabels(labels []string) []string {
+
+	var res []string
+
+	seen := make(map[string]bool, len(labels))
 
-		iTeam, err, ok = database.GetTeamFromDB(ctx, author.TeamName)
+	for _, label := range labels {
 
-		if err != nil || !ok {
+		if !seen[label] {
 
-			return models.PRResponse{}, errs.ErrDatabase
+			seen[label] = true
+
+			res = append(res, label)
 
 		}
 
-		cache.TeamCache.Set(author.TeamName, iTeam.(models.Team))
+	}
+
+	return res
+
+}
+
+// recentPairs counts how often each member recently reviewed pull requests of the author, nil when the penalty is off
+func recentPairs(ctx context.Context, authorID string) (map[string]int, error) {
+
+	policy := assignment.Load()
+
+	if policy.PairWindow == 0 || policy.PairWeight == 0 {
+
+		return nil, nil
 
 	}
 
-	reqTeam := iTeam.(models.Team)
+	return repository.Default.RecentPairs(ctx, authorID, policy.PairWindow)
 
-	counter := 0
+}
 
-	for _, j := range reqTeam.Members {
+// pickReviewers chooses active team members other than the author by the assignment strategy
+// Members go first with fewer reviews in the batch (load, updated when given) plus PairWeight per recent review of the author
+// The team rules in need are met by skipping preferred members where necessary, ok is false when they cannot be
+func pickReviewers(members []models.TeamMember, authorID string, load map[string]int, pairs map[string]int, need rules) ([]string, bool) {
 
-		if j.UserID == author.UserID {
+	policy := assignment.Load()
 
-			continue
+	candidates := make([]models.TeamMember, 0, len(members))
+
+	for _, j := range members {
+
+		if j.UserID != authorID && j.IsActive {
+
+			candidates = append(candidates, j)
 
 		}
 
-		if j.IsActive {
+	}
 
-			counter++
+	rankCandidates(policy, candidates, load, pairs)
 
-			req.AssignedReviewers = append(req.AssignedReviewers, j.UserID)
+	picked, ok := selectReviewers(candidates, policy.Reviewers, need)
 
-		}
+	if !ok {
+
+		return nil, false
 
-		if counter == 2 {
+	}
 
-			break
+	reviewers := make([]string, 0, len(picked))
 
-		}
+	for _, j := range picked {
+
+		reviewers = append(reviewers, j.UserID)
 
 	}
 
-	cache.PRcache.Set(bindedPR.PullRequestID, req)
+	if load != nil {
 
-	err = database.SetPRToDB(ctx, req)
+		for _, id := range reviewers {
 
-	if err != nil {
+			load[id]++
 
-		return models.PRResponse{}, errs.ErrDatabase
+		}
 
 	}
 
-	return models.PRResponse{PullRequest: req}, nil
+	return reviewers, true
 
 }
 
-// Merge updates a pull request status to MERGED (idempotent operation)
-func Merge(ctx context.Context, bindedPR models.PullRequestShort) (models.PRResponse, error) {
+// rankCandidates orders candidates in place, preferred first, for both new pull requests and replacements
+// The strategy sets the order of equally scored members, the score is the load plus PairWeight per recent pair
+func rankCandidates(policy *config.Assignment, candidates []models.TeamMember, load map[string]int, pairs map[string]int) {
 
-	var err error
+	if policy.Strategy == config.StrategyRandom {
 
-	iPR, ok := cache.PRcache.Get(bindedPR.PullRequestID)
+		rand.Shuffle(len(candidates), func(a, b int) { candidates[a], candidates[b] = candidates[b], candidates[a] })
 
-	if !ok {
+	}
 
-		iPR, err, ok = database.GetPRFromDB(ctx, bindedPR.PullRequestID)
+	score := func(id string) float64 { return float64(load[id]) + policy.PairWeight*float64(pairs[id]) }
 
-		if err != nil {
+	// Stable, so equally scored members keep the strategy order
+	sort.SliceStable(candidates, func(a, b int) bool { return score(candidates[a].UserID) < score(candidates[b].UserID) })
 
-			return models.PRResponse{}, errs.ErrDatabase
+}
 
-		}
+// created records a written pull request in metrics and notifies its reviewers
+func created(req models.PullRequest) {
 
-		if !ok {
+	metrics.PRCreatedTotal.Inc()
 
-			return models.PRResponse{}, errs.ErrNotFound
+	events.Default.PublishReview(events.ReviewAssigned, req, req.AssignedReviewers...)
 
-		}
+}
+
+// Merge updates a pull request status to MERGED (idempotent operation)
+func Merge(ctx context.Context, bindedPR models.PRMerge) (models.PRResponse, error) {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.Merge", attribute.String("pull_request.id", bindedPR.PullRequestID))
+
+	defer span.End()
+
+	req, err, ok := repository.Default.GetPR(ctx, bindedPR.PullRequestID)
+
+	if err != nil {
+
+		return models.PRResponse{}, errs.ErrDatabase
 
 	}
 
-	req := iPR.(models.PullRequest)
+	if !ok {
+
+		return models.PRResponse{}, errs.ErrNotFound
+
+	}
 
 	if req.Status == MergeStatus {
 
@@ -173,9 +343,7 @@ func Merge(ctx context.Context, bindedPR models.PullRequestShort) (models.PRResp
 
 	req.MergedAt = time.Now().UTC().Format(time.RFC3339)
 
-	cache.PRcache.Set(bindedPR.PullRequestID, req)
-
-	err = database.SetPRToDB(ctx, req)
+	err = repository.Default.SetPR(ctx, req)
 
 	if err != nil {
 
@@ -183,60 +351,113 @@ func Merge(ctx context.Context, bindedPR models.PullRequestShort) (models.PRResp
 
 	}
 
+	merged(req)
+
 	return models.PRResponse{PullRequest: req}, nil
 
 }
 
+// merged records a written merge in metrics and notifies the reviewers
+func merged(req models.PullRequest) {
+
+	metrics.PRMergedTotal.Inc()
+
+	if createdAt, err := time.Parse(time.RFC3339, req.CreatedAt); err == nil {
+
+		metrics.TimeToMerge.Observe(time.Since(createdAt).Seconds())
+
+	}
+
+	events.Default.PublishReview(events.ReviewMerged, req, req.AssignedReviewers...)
+
+}
+
 // Reassign replaces a reviewer with another active team member
+// ReplacedBy keeps the original API contract and names the reviewer taken off the pull request
 func Reassign(ctx context.Context, bindedPR models.PRReassign) (models.PRReassignResponse, error) {
 
-	var err error
+	ctx, span := tracing.Start(ctx, "pullrequest.Reassign", attribute.String("pull_request.id", bindedPR.PullRequestID), attribute.String("reviewer.id", bindedPR.OldReviewerID))
 
-	iPR, ok := cache.PRcache.Get(bindedPR.PullRequestID)
+	defer span.End()
 
-	if !ok {
+	res, err := reassign(ctx, bindedPR, "reassign", models.PRHistoryEntry{Action: HistoryReassigned})
 
-		iPR, err, ok = database.GetPRFromDB(ctx, bindedPR.PullRequestID)
+	if err != nil {
 
-		if err != nil {
+		return res, err
 
-			return models.PRReassignResponse{}, errs.ErrDatabase
+	}
 
-		}
+	res.ReplacedBy = bindedPR.OldReviewerID
 
-		if !ok {
+	return res, nil
 
-			return models.PRReassignResponse{}, errs.ErrNotFound
+}
 
-		}
+// Escalate reassigns an overdue review found by the SLA scheduler and records why in the pull request history
+func Escalate(ctx context.Context, review models.StaleReview) (models.PRReassignResponse, error) {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.Escalate", attribute.String("pull_request.id", review.PullRequestID), attribute.String("reviewer.id", review.ReviewerID))
+
+	defer span.End()
+
+	reason := fmt.Sprintf("no review within %d hours of assignment", review.SLASeconds/3600)
+
+	res, err := reassign(ctx, models.PRReassign{PullRequestID: review.PullRequestID, OldReviewerID: review.ReviewerID}, "escalate", models.PRHistoryEntry{Action: HistoryEscalated, Reason: reason})
+
+	if err == nil {
+
+		metrics.EscalationsTotal.Inc()
 
 	}
 
-	req := iPR.(models.PullRequest)
+	return res, err
 
-	iUser, ok := cache.UserCache.Get(bindedPR.OldReviewerID)
+}
 
-	if !ok {
+// Decline replaces a reviewer at their own request and records the reason
+// ReplacedBy names the new reviewer, the caller already knows the old one
+func Decline(ctx context.Context, bindedPR models.PRDecline) (models.PRReassignResponse, error) {
 
-		iUser, err, ok = database.GetUserFromDB(ctx, bindedPR.OldReviewerID)
+	ctx, span := tracing.Start(ctx, "pullrequest.Decline", attribute.String("pull_request.id", bindedPR.PullRequestID), attribute.String("reviewer.id", bindedPR.UserID))
 
-		if err != nil {
+	defer span.End()
 
-			return models.PRReassignResponse{}, errs.ErrDatabase
+	return reassign(ctx, models.PRReassign{PullRequestID: bindedPR.PullRequestID, OldReviewerID: bindedPR.UserID}, "decline", models.PRHistoryEntry{Action: HistoryDeclined, Reason: bindedPR.Reason})
 
-		}
+}
 
-		if !ok {
+// reassign replaces the reviewer and appends entry, completed with both reviewers, to the history
+// operation labels NO_CANDIDATE metrics, ReplacedBy of the result is the new reviewer
+func reassign(ctx context.Context, bindedPR models.PRReassign, operation string, entry models.PRHistoryEntry) (models.PRReassignResponse, error) {
 
-			return models.PRReassignResponse{}, errs.ErrNotFound
+	req, err, ok := repository.Default.GetPR(ctx, bindedPR.PullRequestID)
 
-		}
+	if err != nil {
+
+		return models.PRReassignResponse{}, errs.ErrDatabase
+
+	}
+
+	if !ok {
+
+		return models.PRReassignResponse{}, errs.ErrNotFound
+
+	}
+
+	reviewer, err, ok := repository.Default.GetUser(ctx, bindedPR.OldReviewerID)
+
+	if err != nil {
 
-		cache.UserCache.Set(bindedPR.OldReviewerID, iUser.(models.User))
+		return models.PRReassignResponse{}, errs.ErrDatabase
 
 	}
 
-	reviewer := iUser.(models.User)
+	if !ok {
+
+		return models.PRReassignResponse{}, errs.ErrNotFound
+
+	}
 
 	if req.Status == MergeStatus {
 
@@ -268,26 +489,50 @@ func Reassign(ctx context.Context, bindedPR models.PRReassign) (models.PRReassig
 
 	}
 
-	iTeam, ok := cache.TeamCache.Get(reviewer.TeamName)
+	history, err := repository.Default.GetPRHistory(ctx, req.PullRequestID)
 
-	if !ok {
+	if err != nil {
 
-		iTeam, err, ok = database.GetTeamFromDB(ctx, reviewer.TeamName)
+		return models.PRReassignResponse{}, errs.ErrDatabase
 
-		if err != nil || !ok {
+	}
 
-			return models.PRReassignResponse{}, errs.ErrDatabase
+	for _, past := range history { // Whoever declined this pull request is not asked again
+
+		if past.Action == HistoryDeclined {
+
+			stopUserMap[past.UserID]++
 
 		}
 
-		cache.TeamCache.Set(reviewer.TeamName, iTeam.(models.Team))
+	}
+
+	reqTeam, err, ok := repository.Default.GetTeam(ctx, reviewer.TeamName)
+
+	if err != nil || !ok {
+
+		return models.PRReassignResponse{}, errs.ErrDatabase
 
 	}
 
-	reqTeam := iTeam.(models.Team)
+	policy, err, _ := repository.Default.GetTeamPolicy(ctx, reviewer.TeamName)
+
+	if err != nil {
+
+		return models.PRReassignResponse{}, errs.ErrDatabase
+
+	}
+
+	var kept, candidates []models.TeamMember
 
 	for _, k := range reqTeam.Members {
 
+		if k.UserID != bindedPR.OldReviewerID && slices.Contains(req.AssignedReviewers, k.UserID) {
+
+			kept = append(kept, k)
+
+		}
+
 		if _, ok := stopUserMap[k.UserID]; ok {
 
 			continue
@@ -296,32 +541,103 @@ func Reassign(ctx context.Context, bindedPR models.PRReassign) (models.PRReassig
 
 		if k.IsActive {
 
-			req.AssignedReviewers[index] = k.UserID
+			candidates = append(candidates, k)
+
+		}
+
+	}
 
-			cache.PRcache.Set(bindedPR.PullRequestID, req)
+	pairs, err := recentPairs(ctx, req.AuthorID)
 
-			err = database.SetPRToDB(ctx, req)
+	if err != nil {
 
-			if err != nil {
+		return models.PRReassignResponse{}, errs.ErrDatabase
 
-				return models.PRReassignResponse{}, errs.ErrDatabase
+	}
 
-			}
+	rankCandidates(assignment.Load(), candidates, nil, pairs) // Ranked exactly as for a new pull request
 
-			return models.PRReassignResponse{PullRequest: req, ReplacedBy: reviewer.UserID}, nil
+	// The replacement covers whatever the team rules still need next to the reviewers that stay
+	picked, ok := selectReviewers(candidates, 1, rulesFor(policy, req.Labels).without(kept))
 
-		}
+	if !ok || len(picked) == 0 {
+
+		metrics.NoCandidateTotal.WithLabelValues(operation).Inc()
+
+		return models.PRReassignResponse{}, errs.ErrNoCandidate
+
+	}
+
+	req.AssignedReviewers[index] = picked[0].UserID
+
+	entry.UserID, entry.ReplacedBy = bindedPR.OldReviewerID, picked[0].UserID
+
+	err = repository.Default.SetPRWithHistory(ctx, req, entry) // The change and its reason are written together
+
+	if err != nil {
+
+		return models.PRReassignResponse{}, errs.ErrDatabase
+
+	}
+
+	metrics.ReassignmentsTotal.Inc()
+
+	events.Default.PublishReview(events.ReviewUnassigned, req, bindedPR.OldReviewerID)
+
+	events.Default.PublishReview(events.ReviewAssigned, req, picked[0].UserID)
+
+	return models.PRReassignResponse{PullRequest: req, ReplacedBy: picked[0].UserID}, nil
+
+}
+
+// History returns the recorded reviewer changes of a pull request
+func History(ctx context.Context, prID string) (models.PRHistory, error) {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.History", attribute.String("pull_request.id", prID))
+
+	defer span.End()
+
+	_, err, ok := repository.Default.GetPR(ctx, prID)
+
+	if err != nil {
+
+		return models.PRHistory{}, errs.ErrDatabase
+
+	}
+
+	if !ok {
+
+		return models.PRHistory{}, errs.ErrNotFound
+
+	}
+
+	history, err := repository.Default.GetPRHistory(ctx, prID)
+
+	if err != nil {
+
+		return models.PRHistory{}, errs.ErrDatabase
 
 	}
 
-	return models.PRReassignResponse{}, errs.ErrNoCandidate
+	if history == nil {
+
+		history = []models.PRHistoryEntry{}
+
+	}
+
+	return models.PRHistory{PullRequestID: prID, History: history}, nil
 
 }
 
-// GetPR retrieves all pull requests assigned to a user
-func GetPR(ctx context.Context, UserID string) models.UserRequests {
+// GetPR retrieves the review queue of a user: pull requests the user is assigned to, matching filter,
+// open ones first, then by priority from urgent to low, then oldest first
+func GetPR(ctx context.Context, UserID string, filter models.ReviewFilter) models.UserRequests {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.GetPR", attribute.String("user.id", UserID), attribute.String("pull_request.status", filter.Status), attribute.String("pull_request.label", filter.Label))
 
-	res, err := database.GetPRFromDBByUser(ctx, UserID)
+	defer span.End()
+
+	res, err := repository.Default.GetUserReviews(ctx, UserID, filter)
 
 	if err != nil {
 
@@ -332,3 +648,22 @@ func GetPR(ctx context.Context, UserID string) models.UserRequests {
 	return res
 
 }
+
+// List returns up to limit most recent pull requests, optionally filtered by status
+func List(ctx context.Context, status string, limit int) (models.PullRequestList, error) {
+
+	ctx, span := tracing.Start(ctx, "pullrequest.List", attribute.String("pull_request.status", status), attribute.Int("limit", limit))
+
+	defer span.End()
+
+	prs, err := repository.Default.ListPRs(ctx, limit, status)
+
+	if err != nil {
+
+		return models.PullRequestList{}, errs.ErrDatabase
+
+	}
+
+	return models.PullRequestList{PullRequests: prs}, nil
+
+}
@@ -0,0 +1,139 @@
+package pullrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+func TestReassign_ReplacedByIsOldReviewer(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.prs["pr1"] = models.PullRequest{PullRequestID: "pr1", AuthorID: "u1", Status: OpenStatus, AssignedReviewers: []string{"u2", "u3"}}
+
+	res, err := Reassign(context.Background(), models.PRReassign{PullRequestID: "pr1", OldReviewerID: "u2"})
+
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"u4", "u3"}, res.PullRequest.AssignedReviewers)
+
+	// The API has always reported the reviewer taken off, only the history names both
+	assert.Equal(t, "u2", res.ReplacedBy)
+
+	assert.Equal(t, []models.PRHistoryEntry{{Action: HistoryReassigned, UserID: "u2", ReplacedBy: "u4"}}, repo.history["pr1"])
+
+}
+
+func TestReassign_RanksByStrategyAndPairs(t *testing.T) {
+
+	for _, strategy := range []string{config.StrategyOrdered, config.StrategyRandom} {
+
+		t.Run(strategy, func(t *testing.T) {
+
+			repo := useMemRepo(t)
+
+			repo.team.Members = append(repo.team.Members, models.TeamMember{UserID: "u5", IsActive: true}, models.TeamMember{UserID: "u6", IsActive: true})
+
+			Init(config.Assignment{Reviewers: 2, Strategy: strategy, PairWindow: time.Hour, PairWeight: 1})
+
+			repo.prs["pr0"] = models.PullRequest{PullRequestID: "pr0", AuthorID: "u1", Status: MergeStatus, AssignedReviewers: []string{"u4"}}
+
+			picked := map[string]int{}
+
+			for i := 0; i < 50; i++ {
+
+				repo.prs["pr1"] = models.PullRequest{PullRequestID: "pr1", AuthorID: "u1", Status: OpenStatus, AssignedReviewers: []string{"u2", "u3"}}
+
+				res, err := Reassign(context.Background(), models.PRReassign{PullRequestID: "pr1", OldReviewerID: "u2"})
+
+				require.NoError(t, err)
+
+				picked[res.PullRequest.AssignedReviewers[0]]++
+
+			}
+
+			// u4 recently reviewed the author, so it is never preferred over u5 and u6
+			assert.Zero(t, picked["u4"])
+
+			if strategy == config.StrategyOrdered {
+
+				assert.Equal(t, map[string]int{"u5": 50}, picked)
+
+			} else {
+
+				assert.Positive(t, picked["u5"])
+
+				assert.Positive(t, picked["u6"])
+
+			}
+
+		})
+
+	}
+
+}
+
+func TestEscalate_RecordsReason(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.prs["pr1"] = models.PullRequest{PullRequestID: "pr1", AuthorID: "u1", Status: OpenStatus, AssignedReviewers: []string{"u2", "u3"}}
+
+	res, err := Escalate(context.Background(), models.StaleReview{PullRequestID: "pr1", ReviewerID: "u2", SLASeconds: 48 * 3600})
+
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"u4", "u3"}, res.PullRequest.AssignedReviewers)
+
+	assert.Equal(t, "u4", res.ReplacedBy) // The new reviewer, as recorded in the history
+
+	assert.Equal(t, []models.PRHistoryEntry{{Action: HistoryEscalated, UserID: "u2", ReplacedBy: "u4", Reason: "no review within 48 hours of assignment"}}, repo.history["pr1"])
+
+}
+
+func TestEscalate_NoCandidateKeepsHistory(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.team.Members[3].IsActive = false
+
+	repo.prs["pr1"] = models.PullRequest{PullRequestID: "pr1", AuthorID: "u1", Status: OpenStatus, AssignedReviewers: []string{"u2", "u3"}}
+
+	_, err := Escalate(context.Background(), models.StaleReview{PullRequestID: "pr1", ReviewerID: "u2"})
+
+	assert.ErrorIs(t, err, errs.ErrNoCandidate)
+
+	assert.Empty(t, repo.history)
+
+}
+
+func TestDecline_SkipsPastDecliners(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.prs["pr1"] = models.PullRequest{PullRequestID: "pr1", AuthorID: "u1", Status: OpenStatus, AssignedReviewers: []string{"u2", "u3"}}
+
+	res, err := Decline(context.Background(), models.PRDecline{PullRequestID: "pr1", UserID: "u2", Reason: "on vacation"})
+
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"u4", "u3"}, res.PullRequest.AssignedReviewers)
+
+	assert.Equal(t, "u4", res.ReplacedBy)
+
+	// u2 declined before, so only the author would be left to replace u4
+	_, err = Decline(context.Background(), models.PRDecline{PullRequestID: "pr1", UserID: "u4", Reason: "not my area"})
+
+	assert.ErrorIs(t, err, errs.ErrNoCandidate)
+
+	assert.Equal(t, []models.PRHistoryEntry{{Action: HistoryDeclined, UserID: "u2", ReplacedBy: "u4", Reason: "on vacation"}}, repo.history["pr1"])
+
+}
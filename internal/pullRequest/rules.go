@@ -0,0 +1,267 @@
+package pullrequest
+
+import (
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// rules are the reviewer requirements of a team policy for one pull request
+type rules struct {
+	seniors int // senior reviewers still needed
+
+	skills []string // pull request labels still needing a reviewer with the same skill
+}
+
+// rulesFor returns the requirements policy sets for a pull request with labels
+func rulesFor(policy models.TeamPolicy, labels []string) rules {
+
+	need := rules{seniors: policy.MinSeniorReviewers}
+
+	for _, label := range labels {
+
+		for _, skill := range policy.SkillLabels {
+
+			if label == skill {
+
+				need.skills = append(need.skills, label)
+
+				break
+
+			}
+
+		}
+
+	}
+
+	return need
+
+}
+
+// without returns the requirements left once reviewers are already assigned
+func (r rules) without(reviewers []models.TeamMember) rules {
+
+	left := rules{seniors: r.seniors}
+
+	for _, reviewer := range reviewers {
+
+		if reviewer.Level == models.LevelSenior {
+
+			left.seniors--
+
+		}
+
+	}
+
+	for _, skill := range r.skills {
+
+		covered := false
+
+		for _, reviewer := range reviewers {
+
+			if hasSkill(reviewer, skill) {
+
+				covered = true
+
+				break
+
+			}
+
+		}
+
+		if !covered {
+
+			left.skills = append(left.skills, skill)
+
+		}
+
+	}
+
+	return left
+
+}
+
+func hasSkill(member models.TeamMember, skill string) bool {
+
+	for _, s := range member.Skills {
+
+		if s == skill {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+// capability is what a candidate contributes to the rules, candidates with equal ones are interchangeable
+type capability struct {
+	senior bool
+
+	skills uint64 // bit i set when the candidate has rules.skills[i]
+}
+
+// capabilityOf describes member against need, at most 64 skills are told apart which the label limit keeps under
+func capabilityOf(member models.TeamMember, need rules) capability {
+
+	c := capability{senior: member.Level == models.LevelSenior}
+
+	for i, skill := range need.skills {
+
+		if i < 64 && hasSkill(member, skill) {
+
+			c.skills |= 1 << i
+
+		}
+
+	}
+
+	return c
+
+}
+
+// selectReviewers picks up to n candidates, earlier ones preferred, so that the picked reviewers meet need
+// ok is false when no choice of candidates meets it
+func selectReviewers(candidates []models.TeamMember, n int, need rules) ([]models.TeamMember, bool) {
+
+	if n > len(candidates) {
+
+		n = len(candidates)
+
+	}
+
+	if n < 0 {
+
+		n = 0
+
+	}
+
+	pool := make(map[capability]int)
+
+	for _, c := range candidates {
+
+		pool[capabilityOf(c, need)]++
+
+	}
+
+	seniors, missing := need.seniors, allSkills(need)
+
+	if !feasible(pool, n, seniors, missing) {
+
+		return nil, false
+
+	}
+
+	picked := make([]models.TeamMember, 0, n)
+
+	taken := make([]bool, len(candidates))
+
+	for len(picked) < n {
+
+		// The first candidate that still leaves the rules reachable is taken,
+		// one always exists because the remaining slots could be filled before
+		for i, c := range candidates {
+
+			if taken[i] {
+
+				continue
+
+			}
+
+			capab := capabilityOf(c, need)
+
+			left, leftMissing := seniors, missing&^capab.skills
+
+			if capab.senior {
+
+				left--
+
+			}
+
+			pool[capab]--
+
+			if feasible(pool, n-len(picked)-1, left, leftMissing) {
+
+				taken[i] = true
+
+				picked = append(picked, c)
+
+				seniors, missing = left, leftMissing
+
+				break
+
+			}
+
+			pool[capab]++
+
+		}
+
+	}
+
+	return picked, true
+
+}
+
+// allSkills has a bit set for every skill of need
+func allSkills(need rules) uint64 {
+
+	if len(need.skills) >= 64 {
+
+		return ^uint64(0)
+
+	}
+
+	return 1<<len(need.skills) - 1
+
+}
+
+// feasible reports whether slots more candidates from pool can bring in seniors seniors and the missing skills
+// Only candidates that help are tried, the rest can fill any slots left over
+func feasible(pool map[capability]int, slots int, seniors int, missing uint64) bool {
+
+	if seniors <= 0 && missing == 0 {
+
+		return true
+
+	}
+
+	if slots == 0 {
+
+		return false
+
+	}
+
+	for capab, count := range pool {
+
+		if count == 0 || !(capab.senior && seniors > 0 || capab.skills&missing != 0) {
+
+			continue
+
+		}
+
+		left := seniors
+
+		if capab.senior {
+
+			left--
+
+		}
+
+		pool[capab]--
+
+		ok := feasible(pool, slots-1, left, missing&^capab.skills)
+
+		pool[capab]++
+
+		if ok {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
@@ -0,0 +1,118 @@
+package pullrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+func TestSelectReviewers(t *testing.T) {
+
+	candidates := []models.TeamMember{
+
+		{UserID: "u2", Level: models.LevelJunior},
+
+		{UserID: "u3", Level: models.LevelMiddle, Skills: []string{"db"}},
+
+		{UserID: "u4", Level: models.LevelSenior},
+
+		{UserID: "u5", Level: models.LevelSenior, Skills: []string{"db"}},
+	}
+
+	ids := func(members []models.TeamMember) []string {
+
+		var res []string
+
+		for _, m := range members {
+
+			res = append(res, m.UserID)
+
+		}
+
+		return res
+
+	}
+
+	picked, ok := selectReviewers(candidates, 2, rules{})
+
+	assert.True(t, ok)
+
+	assert.Equal(t, []string{"u2", "u3"}, ids(picked)) // No rules, order decides
+
+	picked, ok = selectReviewers(candidates, 2, rules{seniors: 1})
+
+	assert.True(t, ok)
+
+	assert.Equal(t, []string{"u2", "u4"}, ids(picked))
+
+	// u3 or u4 alone would leave the other rule unmet in the one slot left, only u5 covers both
+	picked, ok = selectReviewers(candidates, 1, rules{seniors: 1, skills: []string{"db"}})
+
+	assert.True(t, ok)
+
+	assert.Equal(t, []string{"u5"}, ids(picked))
+
+	_, ok = selectReviewers(candidates, 2, rules{seniors: 3})
+
+	assert.False(t, ok)
+
+	_, ok = selectReviewers(candidates, 2, rules{skills: []string{"frontend"}})
+
+	assert.False(t, ok)
+
+}
+
+func TestCreate_TeamRules(t *testing.T) {
+
+	repo := useMemRepo(t)
+
+	repo.team.Members[3].Level, repo.team.Members[3].Skills = models.LevelSenior, []string{"security"}
+
+	repo.policy = models.TeamPolicy{MinSeniorReviewers: 1, SkillLabels: []string{"security"}}
+
+	res, err := Create(context.Background(), models.PullRequestShort{PullRequestID: "pr1", PullRequestName: "a", AuthorID: "u1", Labels: []string{"security"}})
+
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"u2", "u4"}, res.PullRequest.AssignedReviewers)
+
+	// The only senior is asked to step down, nobody else can take the place
+	_, err = Reassign(context.Background(), models.PRReassign{PullRequestID: "pr1", OldReviewerID: "u4"})
+
+	assert.ErrorIs(t, err, errs.ErrNoCandidate)
+
+	_, err = Create(context.Background(), models.PullRequestShort{PullRequestID: "pr2", PullRequestName: "b", AuthorID: "u4"})
+
+	assert.ErrorIs(t, err, errs.ErrNoCandidate) // The author cannot review their own pull request
+
+}
+
+func TestCreate_RotatesRepeatPairs(t *testing.T) {
+
+	useMemRepo(t)
+
+	Init(config.Assignment{Reviewers: 2, Strategy: config.StrategyOrdered, PairWindow: time.Hour, PairWeight: 1})
+
+	var got [][]string
+
+	for _, id := range []string{"pr1", "pr2", "pr3"} {
+
+		res, err := Create(context.Background(), models.PullRequestShort{PullRequestID: id, PullRequestName: id, AuthorID: "u1"})
+
+		require.NoError(t, err)
+
+		got = append(got, res.PullRequest.AssignedReviewers)
+
+	}
+
+	// Ordered alone would give u2 and u3 every time
+	assert.Equal(t, [][]string{{"u2", "u3"}, {"u4", "u2"}, {"u3", "u4"}}, got)
+
+}
@@ -0,0 +1,299 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+)
+
+// Budgets, read requests are GET and HEAD, everything else is a write
+const (
+	BudgetRead = "read"
+
+	BudgetWrite = "write"
+)
+
+// idleTTL is how long a client without requests keeps its bucket
+const idleTTL = 10 * time.Minute
+
+// overflowKey is the bucket shared by new clients once a limiter tracks its maximum
+const overflowKey = "overflow"
+
+var (
+	reads = New(0, 0)
+
+	writes = New(0, 0)
+)
+
+// identity tells callers apart, replaced as a whole by Init
+type identity struct {
+	tokens map[string]bool // API tokens that get their own budget
+
+	extractIP echo.IPExtractor
+}
+
+var callers atomic.Pointer[identity]
+
+func init() {
+
+	callers.Store(&identity{extractIP: echo.ExtractIPDirect()})
+
+}
+
+// Limiter keeps a token bucket per client key
+type Limiter struct {
+	limit rate.Limit // zero disables the limiter
+
+	burst int
+
+	clients map[string]*client
+
+	lastSweep time.Time
+
+	maxClients int // zero tracks any number of clients
+
+	mu sync.Mutex
+}
+
+type client struct {
+	bucket *rate.Limiter
+
+	seen time.Time
+}
+
+// New creates a limiter allowing perSecond requests with bursts of burst per client, zero perSecond allows everything
+func New(perSecond float64, burst int) *Limiter {
+
+	return &Limiter{limit: rate.Limit(perSecond), burst: burst, clients: make(map[string]*client)}
+
+}
+
+// Init sets both budgets, the known tokens and the trusted proxies, it is safe to call while requests are served
+func Init(cfg config.RateLimit) {
+
+	reads.SetLimit(cfg.ReadRate, cfg.ReadBurst)
+
+	writes.SetLimit(cfg.WriteRate, cfg.WriteBurst)
+
+	reads.SetMaxClients(cfg.MaxClients)
+
+	writes.SetMaxClients(cfg.MaxClients)
+
+	next := &identity{tokens: make(map[string]bool), extractIP: echo.ExtractIPDirect()}
+
+	for _, token := range config.SplitList(cfg.Tokens) {
+
+		next.tokens[token] = true
+
+	}
+
+	if proxies := config.SplitList(cfg.TrustedProxies); len(proxies) != 0 {
+
+		// Only the listed proxies are trusted, not the private ranges echo trusts by default
+		opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+
+		for _, cidr := range proxies {
+
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil { // Config validation already rejected bad ones
+
+				opts = append(opts, echo.TrustIPRange(ipNet))
+
+			}
+
+		}
+
+		next.extractIP = echo.ExtractIPFromXFFHeader(opts...)
+
+	}
+
+	callers.Store(next)
+
+}
+
+// ExtractIP is the echo IP extractor, X-Forwarded-For is read only when it comes from a trusted proxy
+func ExtractIP(req *http.Request) string {
+
+	return callers.Load().extractIP(req)
+
+}
+
+// SetMaxClients caps the clients tracked at once, zero removes the cap
+func (l *Limiter) SetMaxClients(max int) {
+
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	l.maxClients = max
+
+}
+
+// SetLimit changes the rate of every client, buckets keep their tokens up to the new burst
+func (l *Limiter) SetLimit(perSecond float64, burst int) {
+
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	l.limit = rate.Limit(perSecond)
+
+	l.burst = burst
+
+	now := time.Now()
+
+	for _, c := range l.clients {
+
+		c.bucket.SetLimitAt(now, l.limit)
+
+		c.bucket.SetBurstAt(now, burst)
+
+	}
+
+}
+
+// Allow takes a token of key, when none is left it reports how long to wait for the next one
+func (l *Limiter) Allow(key string, now time.Time) (bool, time.Duration) {
+
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	if l.limit == 0 {
+
+		return true, 0
+
+	}
+
+	if now.Sub(l.lastSweep) > idleTTL {
+
+		l.sweep(now)
+
+	}
+
+	c, ok := l.clients[key]
+
+	if !ok && l.maxClients > 0 && len(l.clients) >= l.maxClients {
+
+		l.sweep(now)
+
+		if len(l.clients) >= l.maxClients { // Made up keys must not grow the map, they share one bucket
+
+			key = overflowKey
+
+			c, ok = l.clients[key]
+
+		}
+
+	}
+
+	if !ok {
+
+		c = &client{bucket: rate.NewLimiter(l.limit, l.burst)}
+
+		l.clients[key] = c
+
+	}
+
+	c.seen = now
+
+	reservation := c.bucket.ReserveN(now, 1)
+
+	if delay := reservation.DelayFrom(now); delay > 0 {
+
+		reservation.CancelAt(now) // A rejected request does not use up future tokens
+
+		return false, delay
+
+	}
+
+	return true, 0
+
+}
+
+// sweep drops clients idle for idleTTL, their buckets are full again anyway
+// must be called with lock held
+func (l *Limiter) sweep(now time.Time) {
+
+	for key, c := range l.clients {
+
+		if now.Sub(c.seen) > idleTTL {
+
+			delete(l.clients, key)
+
+		}
+
+	}
+
+	l.lastSweep = now
+
+}
+
+// ClientKey identifies the caller by a configured bearer token, or by IP for any other request
+// Unknown tokens are ignored, otherwise a new token per request would get a new full bucket every time
+func ClientKey(c echo.Context) string {
+
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+
+	if token, ok := strings.CutPrefix(auth, "Bearer "); ok && callers.Load().tokens[token] {
+
+		return "token:" + token
+
+	}
+
+	return "ip:" + c.RealIP()
+
+}
+
+// Middleware answers 429 RATE_LIMITED with Retry-After once a client spends its budget
+// Requests for which skip returns true are not limited
+func Middleware(skip func(c echo.Context) bool) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			if skip(c) {
+
+				return next(c)
+
+			}
+
+			limiter, budget := writes, BudgetWrite
+
+			if method := c.Request().Method; method == http.MethodGet || method == http.MethodHead {
+
+				limiter, budget = reads, BudgetRead
+
+			}
+
+			ok, wait := limiter.Allow(ClientKey(c), time.Now())
+
+			if ok {
+
+				return next(c)
+
+			}
+
+			metrics.RateLimitedTotal.WithLabelValues(budget).Inc()
+
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+
+			return c.JSON(http.StatusTooManyRequests, errs.RateLimited())
+
+		}
+
+	}
+
+}
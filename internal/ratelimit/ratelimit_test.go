@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+
+	now := time.Unix(0, 0)
+
+	l := New(1, 2)
+
+	for i := 0; i < 2; i++ {
+
+		ok, _ := l.Allow("a", now)
+
+		assert.True(t, ok)
+
+	}
+
+	ok, wait := l.Allow("a", now)
+
+	assert.False(t, ok)
+
+	assert.Equal(t, time.Second, wait)
+
+	ok, _ = l.Allow("b", now) // Clients have separate buckets
+
+	assert.True(t, ok)
+
+	ok, _ = l.Allow("a", now.Add(time.Second))
+
+	assert.True(t, ok)
+
+	l.SetLimit(0, 0) // Zero rate disables limiting
+
+	ok, _ = l.Allow("a", now.Add(time.Second))
+
+	assert.True(t, ok)
+
+}
+
+func TestMiddleware_SeparateBudgets(t *testing.T) {
+
+	Init(config.RateLimit{ReadRate: 1, ReadBurst: 1, WriteRate: 1, WriteBurst: 1, Tokens: "script"})
+
+	defer Init(config.RateLimit{})
+
+	e := echo.New()
+
+	e.Use(Middleware(func(c echo.Context) bool { return c.Path() == "/health" }))
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+
+	e.GET("/team/get", ok)
+
+	e.POST("/pullRequest/create", ok)
+
+	e.GET("/health", ok)
+
+	do := func(method string, path string, token string) *httptest.ResponseRecorder {
+
+		req := httptest.NewRequest(method, path, nil)
+
+		if token != "" {
+
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+		}
+
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		return rec
+
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/pullRequest/create", "").Code)
+
+	rec := do(http.MethodPost, "/pullRequest/create", "")
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	assert.Contains(t, rec.Body.String(), string(errs.CodeRateLimited))
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/team/get", "").Code) // Reads have their own budget
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/pullRequest/create", "script").Code) // Tokens are separate clients
+
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodPost, "/pullRequest/create", "made-up").Code) // Unknown tokens count as the IP
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/health", "").Code)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/health", "").Code) // Skipped requests are not limited
+
+}
+
+func TestLimiter_MaxClients(t *testing.T) {
+
+	now := time.Unix(0, 0)
+
+	l := New(1, 1)
+
+	l.SetMaxClients(1)
+
+	ok, _ := l.Allow("a", now)
+
+	assert.True(t, ok)
+
+	ok, _ = l.Allow("b", now) // Beyond the cap new clients share the overflow bucket
+
+	assert.True(t, ok)
+
+	ok, _ = l.Allow("c", now)
+
+	assert.False(t, ok)
+
+	assert.Len(t, l.clients, 2)
+
+}
+
+func TestExtractIP_TrustedProxies(t *testing.T) {
+
+	defer Init(config.RateLimit{})
+
+	req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+
+	req.RemoteAddr = "10.0.0.5:4000"
+
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+
+	Init(config.RateLimit{})
+
+	assert.Equal(t, "10.0.0.5", ExtractIP(req)) // Without trusted proxies the header is ignored
+
+	Init(config.RateLimit{TrustedProxies: "10.0.0.0/24"})
+
+	assert.Equal(t, "203.0.113.7", ExtractIP(req))
+
+	req.RemoteAddr = "198.51.100.1:4000"
+
+	assert.Equal(t, "198.51.100.1", ExtractIP(req)) // Spoofed by a client that is not a proxy
+
+}
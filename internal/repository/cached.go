@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Cached is a read-through cache decorator around a Repository
+// Reads fill the cache from the wrapped repository on miss, writes evict what they touch
+// and the next read loads the committed value, so concurrent writers cannot leave an older value cached
+type Cached struct {
+	repo Repository
+
+	teams *cache.Sharded[string, models.Team]
+
+	users *cache.Sharded[string, models.User]
+
+	prs *cache.Sharded[string, models.PullRequest]
+}
+
+// constructor
+func NewCached(repo Repository, teams *cache.Sharded[string, models.Team],
+	users *cache.Sharded[string, models.User], prs *cache.Sharded[string, models.PullRequest]) *Cached {
+
+	return &Cached{repo: repo, teams: teams, users: users, prs: prs}
+
+}
+
+// GetTeam returns a team from cache or loads it from the repository
+func (c *Cached) GetTeam(ctx context.Context, teamName string) (models.Team, error, bool) {
+
+	if team, ok := c.teams.Get(teamName); ok {
+
+		return cloneTeam(team), nil, true
+
+	}
+
+	team, err, ok := c.repo.GetTeam(ctx, teamName)
+
+	if err != nil || !ok {
+
+		return team, err, ok
+
+	}
+
+	c.teams.Set(teamName, cloneTeam(team))
+
+	return team, nil, true
+
+}
+
+// CreateTeam inserts a new team with its members and evicts them whether or not the transaction commits
+func (c *Cached) CreateTeam(ctx context.Context, team models.Team) error {
+
+	err := c.repo.CreateTeam(ctx, team)
+
+	c.evictTeam(team)
+
+	return err
+
+}
+
+// SetTeam writes a team and its members, then evicts them
+// Teams the members belonged to before are evicted too since their member lists changed
+func (c *Cached) SetTeam(ctx context.Context, team models.Team) ([]string, error) {
+
+	left, err := c.repo.SetTeam(ctx, team)
+
+	for _, teamName := range left { // Reported by the repository, the members may never have been cached here
+
+		c.teams.Delete(teamName)
+
+	}
+
+	c.evictTeam(team)
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	return left, nil
+
+}
+
+// evictTeam drops a written team and its members, a failed write may have partially happened too
+func (c *Cached) evictTeam(team models.Team) {
+
+	c.teams.Delete(team.TeamName)
+
+	for _, member := range team.Members {
+
+		c.users.Delete(member.UserID)
+
+	}
+
+}
+
+// GetUser returns a user from cache or loads it from the repository
+func (c *Cached) GetUser(ctx context.Context, userID string) (models.User, error, bool) {
+
+	if user, ok := c.users.Get(userID); ok {
+
+		return user, nil, true
+
+	}
+
+	user, err, ok := c.repo.GetUser(ctx, userID)
+
+	if err != nil || !ok {
+
+		return user, err, ok
+
+	}
+
+	c.users.Set(userID, user)
+
+	return user, nil, true
+
+}
+
+// GetUserReviews is not cached, it always reads the repository
+func (c *Cached) GetUserReviews(ctx context.Context, userID string, filter models.ReviewFilter) (models.UserRequests, error) {
+
+	return c.repo.GetUserReviews(ctx, userID, filter)
+
+}
+
+// GetPR returns a pull request from cache or loads it from the repository
+func (c *Cached) GetPR(ctx context.Context, prID string) (models.PullRequest, error, bool) {
+
+	if pr, ok := c.prs.Get(prID); ok {
+
+		return clonePR(pr), nil, true
+
+	}
+
+	pr, err, ok := c.repo.GetPR(ctx, prID)
+
+	if err != nil || !ok {
+
+		return pr, err, ok
+
+	}
+
+	c.prs.Set(prID, clonePR(pr))
+
+	return pr, nil, true
+
+}
+
+// SetPR writes a pull request, then evicts it
+func (c *Cached) SetPR(ctx context.Context, pr models.PullRequest) error {
+
+	return c.evictPRs([]models.PullRequest{pr}, c.repo.SetPR(ctx, pr))
+
+}
+
+// CreatePRs inserts new pull requests in one transaction, then evicts them
+func (c *Cached) CreatePRs(ctx context.Context, prs []models.PullRequest) error {
+
+	return c.evictPRs(prs, c.repo.CreatePRs(ctx, prs))
+
+}
+
+// SetPRs writes pull requests in one transaction, then evicts them
+func (c *Cached) SetPRs(ctx context.Context, prs []models.PullRequest) error {
+
+	return c.evictPRs(prs, c.repo.SetPRs(ctx, prs))
+
+}
+
+// evictPRs drops written pull requests and passes err through, whether the write committed or not
+func (c *Cached) evictPRs(prs []models.PullRequest, err error) error {
+
+	for _, pr := range prs {
+
+		c.prs.Delete(pr.PullRequestID)
+
+	}
+
+	return err
+
+}
+
+// ListTeams is not cached, it always reads the repository
+func (c *Cached) ListTeams(ctx context.Context, limit int) ([]models.Team, error) {
+
+	return c.repo.ListTeams(ctx, limit)
+
+}
+
+// ListPRs is not cached, it always reads the repository
+func (c *Cached) ListPRs(ctx context.Context, limit int, status string) ([]models.PullRequest, error) {
+
+	return c.repo.ListPRs(ctx, limit, status)
+
+}
+
+// GetStats is not cached, it always reads the repository
+func (c *Cached) GetStats(ctx context.Context) (models.Stats, error) {
+
+	return c.repo.GetStats(ctx)
+
+}
+
+// Export is not cached, it always reads the repository
+func (c *Cached) Export(ctx context.Context, emit func(models.DumpRecord) error) error {
+
+	return c.repo.Export(ctx, emit)
+
+}
+
+// Import writes a dump and purges every cache, since any cached entity may have been replaced
+func (c *Cached) Import(ctx context.Context, dump models.Dump, policy string) (models.ImportResult, error) {
+
+	res, err := c.repo.Import(ctx, dump, policy)
+
+	c.teams.Purge()
+
+	c.users.Purge()
+
+	c.prs.Purge()
+
+	return res, err
+
+}
+
+// cached values share slices with callers, clone them so callers cannot change the cache in place
+func cloneTeam(team models.Team) models.Team {
+
+	team.Members = slices.Clone(team.Members)
+
+	return team
+
+}
+
+func clonePR(pr models.PullRequest) models.PullRequest {
+
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+
+	pr.Labels = slices.Clone(pr.Labels)
+
+	return pr
+
+}
+
+// StaleReviews is not cached, review ages change every second
+func (c *Cached) StaleReviews(ctx context.Context, defaultSLA time.Duration, teamName string, limit int) ([]models.StaleReview, error) {
+
+	return c.repo.StaleReviews(ctx, defaultSLA, teamName, limit)
+
+}
+
+// ClaimReminders is not cached, it always writes the repository
+func (c *Cached) ClaimReminders(ctx context.Context, defaultSLA time.Duration, remindEvery time.Duration, limit int) ([]models.StaleReview, bool, error) {
+
+	return c.repo.ClaimReminders(ctx, defaultSLA, remindEvery, limit)
+
+}
+
+// GetTeamPolicy is not cached, it always reads the repository
+func (c *Cached) GetTeamPolicy(ctx context.Context, teamName string) (models.TeamPolicy, error, bool) {
+
+	return c.repo.GetTeamPolicy(ctx, teamName)
+
+}
+
+// SetTeamPolicy is not cached, it always writes the repository
+func (c *Cached) SetTeamPolicy(ctx context.Context, policy models.TeamPolicy) error {
+
+	return c.repo.SetTeamPolicy(ctx, policy)
+
+}
+
+// ClaimEscalations is not cached, it always writes the repository
+func (c *Cached) ClaimEscalations(ctx context.Context, limit int) ([]models.StaleReview, bool, error) {
+
+	return c.repo.ClaimEscalations(ctx, limit)
+
+}
+
+// SetPRWithHistory writes a pull request with a history entry, then evicts it
+func (c *Cached) SetPRWithHistory(ctx context.Context, pr models.PullRequest, entry models.PRHistoryEntry) error {
+
+	return c.evictPRs([]models.PullRequest{pr}, c.repo.SetPRWithHistory(ctx, pr, entry))
+
+}
+
+// GetPRHistory is not cached, it always reads the repository
+func (c *Cached) GetPRHistory(ctx context.Context, prID string) ([]models.PRHistoryEntry, error) {
+
+	return c.repo.GetPRHistory(ctx, prID)
+
+}
+
+// RecentPairs is not cached, it always reads the repository
+func (c *Cached) RecentPairs(ctx context.Context, authorID string, window time.Duration) (map[string]int, error) {
+
+	return c.repo.RecentPairs(ctx, authorID, window)
+
+}
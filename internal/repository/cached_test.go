@@ -0,0 +1,479 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+var errInjected = errors.New("injected database failure")
+
+// fakeRepo is an in-memory Repository whose writes can be made to fail
+type fakeRepo struct {
+	teams map[string]models.Team
+
+	users map[string]models.User
+
+	prs map[string]models.PullRequest
+
+	failWrites bool
+
+	reads int
+}
+
+func newFakeRepo() *fakeRepo {
+
+	return &fakeRepo{
+
+		teams: map[string]models.Team{},
+
+		users: map[string]models.User{},
+
+		prs: map[string]models.PullRequest{},
+	}
+
+}
+
+func (f *fakeRepo) GetTeam(_ context.Context, teamName string) (models.Team, error, bool) {
+
+	f.reads++
+
+	team, ok := f.teams[teamName]
+
+	return team, nil, ok
+
+}
+
+func (f *fakeRepo) CreateTeam(ctx context.Context, team models.Team) error {
+
+	_, err := f.SetTeam(ctx, team)
+
+	return err
+
+}
+
+func (f *fakeRepo) SetTeam(_ context.Context, team models.Team) ([]string, error) {
+
+	if f.failWrites {
+
+		return nil, errInjected
+
+	}
+
+	f.teams[team.TeamName] = team
+
+	var left []string
+
+	for _, member := range team.Members {
+
+		if user, ok := f.users[member.UserID]; ok && user.TeamName != team.TeamName {
+
+			left = append(left, user.TeamName)
+
+		}
+
+		f.users[member.UserID] = models.User{UserID: member.UserID, Username: member.Username, TeamName: team.TeamName, IsActive: member.IsActive}
+
+	}
+
+	return left, nil
+
+}
+
+func (f *fakeRepo) GetUser(_ context.Context, userID string) (models.User, error, bool) {
+
+	f.reads++
+
+	user, ok := f.users[userID]
+
+	return user, nil, ok
+
+}
+
+func (f *fakeRepo) GetUserReviews(_ context.Context, userID string, _ models.ReviewFilter) (models.UserRequests, error) {
+
+	return models.UserRequests{UserID: userID}, nil
+
+}
+
+func (f *fakeRepo) GetPR(_ context.Context, prID string) (models.PullRequest, error, bool) {
+
+	f.reads++
+
+	pr, ok := f.prs[prID]
+
+	return pr, nil, ok
+
+}
+
+func (f *fakeRepo) SetPR(_ context.Context, pr models.PullRequest) error {
+
+	if f.failWrites {
+
+		return errInjected
+
+	}
+
+	f.prs[pr.PullRequestID] = pr
+
+	return nil
+
+}
+
+func (f *fakeRepo) CreatePRs(ctx context.Context, prs []models.PullRequest) error {
+
+	return f.SetPRs(ctx, prs)
+
+}
+
+func (f *fakeRepo) SetPRs(_ context.Context, prs []models.PullRequest) error {
+
+	if f.failWrites {
+
+		return errInjected
+
+	}
+
+	for _, pr := range prs {
+
+		f.prs[pr.PullRequestID] = pr
+
+	}
+
+	return nil
+
+}
+
+func (f *fakeRepo) ListTeams(_ context.Context, _ int) ([]models.Team, error) {
+
+	return nil, nil
+
+}
+
+func (f *fakeRepo) ListPRs(_ context.Context, _ int, _ string) ([]models.PullRequest, error) {
+
+	return nil, nil
+
+}
+
+func (f *fakeRepo) GetStats(_ context.Context) (models.Stats, error) {
+
+	return models.Stats{}, nil
+
+}
+
+func (f *fakeRepo) Export(_ context.Context, _ func(models.DumpRecord) error) error {
+
+	return nil
+
+}
+
+func (f *fakeRepo) Import(_ context.Context, _ models.Dump, _ string) (models.ImportResult, error) {
+
+	if f.failWrites {
+
+		return models.ImportResult{}, errInjected
+
+	}
+
+	return models.ImportResult{}, nil
+
+}
+
+func (f *fakeRepo) StaleReviews(_ context.Context, _ time.Duration, _ string, _ int) ([]models.StaleReview, error) {
+
+	return nil, nil
+
+}
+
+func (f *fakeRepo) ClaimReminders(_ context.Context, _ time.Duration, _ time.Duration, _ int) ([]models.StaleReview, bool, error) {
+
+	return nil, false, nil
+
+}
+
+func (f *fakeRepo) GetTeamPolicy(_ context.Context, teamName string) (models.TeamPolicy, error, bool) {
+
+	return models.TeamPolicy{TeamName: teamName}, nil, false
+
+}
+
+func (f *fakeRepo) SetTeamPolicy(_ context.Context, _ models.TeamPolicy) error {
+
+	return nil
+
+}
+
+func (f *fakeRepo) ClaimEscalations(_ context.Context, _ int) ([]models.StaleReview, bool, error) {
+
+	return nil, false, nil
+
+}
+
+func (f *fakeRepo) SetPRWithHistory(ctx context.Context, pr models.PullRequest, _ models.PRHistoryEntry) error {
+
+	return f.SetPR(ctx, pr)
+
+}
+
+func (f *fakeRepo) GetPRHistory(_ context.Context, _ string) ([]models.PRHistoryEntry, error) {
+
+	return nil, nil
+
+}
+
+func (f *fakeRepo) RecentPairs(_ context.Context, _ string, _ time.Duration) (map[string]int, error) {
+
+	return nil, nil
+
+}
+
+func newTestCached(repo Repository) *Cached {
+
+	return NewCached(repo,
+
+		cache.NewSharded[string, models.Team](100, 4, cache.HashString),
+
+		cache.NewSharded[string, models.User](100, 4, cache.HashString),
+
+		cache.NewSharded[string, models.PullRequest](100, 4, cache.HashString))
+
+}
+
+func TestCached_SetPRFailureDoesNotCache(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	pr := models.PullRequest{PullRequestID: "pr1", AuthorID: "u1", Status: "OPEN"}
+
+	repo.failWrites = true
+
+	err := cached.SetPR(ctx, pr)
+
+	assert.ErrorIs(t, err, errInjected)
+
+	_, found := cached.prs.Get("pr1")
+
+	assert.False(t, found)
+
+	// A retried create must not see a phantom PR_EXISTS
+	_, err, ok := cached.GetPR(ctx, "pr1")
+
+	assert.NoError(t, err)
+
+	assert.False(t, ok)
+
+	repo.failWrites = false
+
+	assert.NoError(t, cached.SetPR(ctx, pr))
+
+	got, err, ok := cached.GetPR(ctx, "pr1")
+
+	assert.NoError(t, err)
+
+	assert.True(t, ok)
+
+	assert.Equal(t, pr, got)
+
+}
+
+func TestCached_SetPRFailureEvictsStaleEntry(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	pr := models.PullRequest{PullRequestID: "pr1", Status: "OPEN", AssignedReviewers: []string{"u2"}}
+
+	assert.NoError(t, cached.SetPR(ctx, pr))
+
+	merged := pr
+
+	merged.Status = "MERGED"
+
+	repo.failWrites = true
+
+	assert.ErrorIs(t, cached.SetPR(ctx, merged), errInjected)
+
+	got, err, ok := cached.GetPR(ctx, "pr1")
+
+	assert.NoError(t, err)
+
+	assert.True(t, ok)
+
+	assert.Equal(t, "OPEN", got.Status)
+
+}
+
+func TestCached_SetTeamFailureDoesNotCache(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	team := models.Team{TeamName: "backend", Members: []models.TeamMember{{UserID: "u1", Username: "User1", IsActive: true}}}
+
+	repo.failWrites = true
+
+	_, err := cached.SetTeam(ctx, team)
+
+	assert.ErrorIs(t, err, errInjected)
+
+	_, err, ok := cached.GetTeam(ctx, "backend")
+
+	assert.NoError(t, err)
+
+	assert.False(t, ok)
+
+	_, err, ok = cached.GetUser(ctx, "u1")
+
+	assert.NoError(t, err)
+
+	assert.False(t, ok)
+
+	repo.failWrites = false
+
+	_, err = cached.SetTeam(ctx, team)
+
+	assert.NoError(t, err)
+
+	user, err, ok := cached.GetUser(ctx, "u1") // Loaded from the repository after the write evicted it
+
+	assert.NoError(t, err)
+
+	assert.True(t, ok)
+
+	assert.Equal(t, "backend", user.TeamName)
+
+	reads := repo.reads
+
+	_, _, _ = cached.GetUser(ctx, "u1")
+
+	assert.Equal(t, reads, repo.reads, "user should be served from cache after the first read")
+
+}
+
+func TestCached_ReturnedValuesDoNotAliasCache(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	assert.NoError(t, cached.SetPR(ctx, models.PullRequest{PullRequestID: "pr1", AssignedReviewers: []string{"u1", "u2"}}))
+
+	_, _, _ = cached.GetPR(ctx, "pr1") // Fills the cache
+
+	pr, _, _ := cached.GetPR(ctx, "pr1")
+
+	pr.AssignedReviewers[0] = "u3"
+
+	pr, _, _ = cached.GetPR(ctx, "pr1")
+
+	assert.Equal(t, []string{"u1", "u2"}, pr.AssignedReviewers)
+
+}
+
+func TestCached_MovedMemberEvictsOldTeam(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	_, err := cached.SetTeam(ctx, models.Team{TeamName: "old", Members: []models.TeamMember{{UserID: "u1"}}})
+
+	assert.NoError(t, err)
+
+	_, _, _ = cached.GetTeam(ctx, "old")
+
+	_, _, _ = cached.GetUser(ctx, "u1")
+
+	_, err = cached.SetTeam(ctx, models.Team{TeamName: "new", Members: []models.TeamMember{{UserID: "u1"}}})
+
+	assert.NoError(t, err)
+
+	_, found := cached.teams.Get("old")
+
+	assert.False(t, found)
+
+}
+
+func TestCached_MovedUncachedMemberEvictsOldTeam(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	_, err := cached.SetTeam(ctx, models.Team{TeamName: "old", Members: []models.TeamMember{{UserID: "u1"}}})
+
+	assert.NoError(t, err)
+
+	_, _, _ = cached.GetTeam(ctx, "old") // Loaded without its members being cached
+
+	_, err = cached.SetTeam(ctx, models.Team{TeamName: "new", Members: []models.TeamMember{{UserID: "u1"}}})
+
+	assert.NoError(t, err)
+
+	_, found := cached.teams.Get("old")
+
+	assert.False(t, found)
+
+}
+
+func TestCached_WriteEvictsUntilNextRead(t *testing.T) {
+
+	ctx := context.Background()
+
+	repo := newFakeRepo()
+
+	cached := newTestCached(repo)
+
+	pr := models.PullRequest{PullRequestID: "pr1", Status: "OPEN"}
+
+	assert.NoError(t, cached.SetPR(ctx, pr))
+
+	_, _, _ = cached.GetPR(ctx, "pr1")
+
+	// Two writers commit in turn, whichever finishes last must not decide what stays cached
+	merged := pr
+
+	merged.Status = "MERGED"
+
+	assert.NoError(t, cached.SetPR(ctx, merged))
+
+	_, found := cached.prs.Get("pr1")
+
+	assert.False(t, found)
+
+	got, err, ok := cached.GetPR(ctx, "pr1")
+
+	assert.NoError(t, err)
+
+	assert.True(t, ok)
+
+	assert.Equal(t, "MERGED", got.Status)
+
+	_, found = cached.prs.Get("pr1")
+
+	assert.True(t, found)
+
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Repository is the storage used by the team and pull request services
+// Get methods follow the database convention: value, error, found
+type Repository interface {
+	GetTeam(ctx context.Context, teamName string) (models.Team, error, bool)
+
+	CreateTeam(ctx context.Context, team models.Team) error // insert only, fails with errs.ErrTeamExists when the name is taken
+
+	SetTeam(ctx context.Context, team models.Team) ([]string, error) // teams the members were moved out of
+
+	GetUser(ctx context.Context, userID string) (models.User, error, bool)
+
+	GetUserReviews(ctx context.Context, userID string, filter models.ReviewFilter) (models.UserRequests, error)
+
+	GetPR(ctx context.Context, prID string) (models.PullRequest, error, bool)
+
+	SetPR(ctx context.Context, pr models.PullRequest) error
+
+	SetPRs(ctx context.Context, prs []models.PullRequest) error // all or nothing
+
+	CreatePRs(ctx context.Context, prs []models.PullRequest) error // all or nothing, *errs.PRExistsFailure when one already exists
+
+	ListTeams(ctx context.Context, limit int) ([]models.Team, error)
+
+	ListPRs(ctx context.Context, limit int, status string) ([]models.PullRequest, error)
+
+	GetStats(ctx context.Context) (models.Stats, error)
+
+	Export(ctx context.Context, emit func(models.DumpRecord) error) error // records in dump order, header first
+
+	Import(ctx context.Context, dump models.Dump, policy string) (models.ImportResult, error)
+
+	StaleReviews(ctx context.Context, defaultSLA time.Duration, teamName string, limit int) ([]models.StaleReview, error)
+
+	ClaimReminders(ctx context.Context, defaultSLA time.Duration, remindEvery time.Duration, limit int) ([]models.StaleReview, bool, error) // false when another instance holds the scheduler lock
+
+	GetTeamPolicy(ctx context.Context, teamName string) (models.TeamPolicy, error, bool)
+
+	SetTeamPolicy(ctx context.Context, policy models.TeamPolicy) error
+
+	ClaimEscalations(ctx context.Context, limit int) ([]models.StaleReview, bool, error) // false as in ClaimReminders
+
+	SetPRWithHistory(ctx context.Context, pr models.PullRequest, entry models.PRHistoryEntry) error // both or nothing
+
+	GetPRHistory(ctx context.Context, prID string) ([]models.PRHistoryEntry, error)
+
+	RecentPairs(ctx context.Context, authorID string, window time.Duration) (map[string]int, error) // assignments per reviewer to pull requests of the author
+}
+
+// Default is the cached Postgres repository used by services
+var Default Repository
+
+// Init wraps Postgres with the global caches, call after cache.InitCache
+func Init() {
+
+	Default = NewCached(database.Postgres{}, cache.TeamCache, cache.UserCache, cache.PRcache)
+
+}
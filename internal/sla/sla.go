@@ -0,0 +1,154 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/logger"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// Stale lists reviews overdue by cfg.Default or their team SLA oldest first, an empty teamName means every team
+func Stale(ctx context.Context, cfg config.SLA, teamName string, limit int) (models.StaleReviewList, error) {
+
+	ctx, span := tracing.Start(ctx, "sla.Stale", attribute.String("team.name", teamName))
+
+	defer span.End()
+
+	reviews, err := repository.Default.StaleReviews(ctx, cfg.Default, teamName, limit)
+
+	if err != nil {
+
+		return models.StaleReviewList{}, errs.ErrDatabase
+
+	}
+
+	if reviews == nil {
+
+		reviews = []models.StaleReview{}
+
+	}
+
+	return models.StaleReviewList{Reviews: reviews}, nil
+
+}
+
+// Run sends reminders about overdue reviews and escalates them as team policies say every check interval until ctx is done
+// Every instance runs it, but only the one holding the database lock sends anything
+func Run(ctx context.Context, cfg config.SLA) {
+
+	if cfg.CheckInterval == 0 {
+
+		logger.Info("review reminders disabled")
+
+		return
+
+	}
+
+	ticker := time.NewTicker(cfg.CheckInterval)
+
+	defer ticker.Stop()
+
+	for {
+
+		select {
+
+		case <-ctx.Done():
+
+			return
+
+		case <-ticker.C:
+
+			remind(ctx, cfg)
+
+			escalate(ctx, cfg)
+
+		}
+
+	}
+
+}
+
+// remind claims a batch of overdue reviews, the claim itself notifies every instance to remind the reviewers
+func remind(ctx context.Context, cfg config.SLA) int {
+
+	reviews, locked, err := repository.Default.ClaimReminders(ctx, cfg.Default, cfg.RemindEvery, cfg.BatchSize)
+
+	if err != nil {
+
+		logger.Error(err, "failed to claim review reminders")
+
+		return 0
+
+	}
+
+	if !locked {
+
+		logger.Debug("review reminders are sent by another instance")
+
+		return 0
+
+	}
+
+	for _, review := range reviews {
+
+		metrics.ReviewRemindersTotal.Inc()
+
+		logger.Info("review reminder sent", "pull_request_id", review.PullRequestID, "user_id", review.ReviewerID, "age_seconds", review.AgeSeconds)
+
+	}
+
+	return len(reviews)
+
+}
+
+// escalate claims reviews due for escalation and reassigns each of them
+func escalate(ctx context.Context, cfg config.SLA) int {
+
+	reviews, locked, err := repository.Default.ClaimEscalations(ctx, cfg.BatchSize)
+
+	if err != nil {
+
+		logger.Error(err, "failed to claim review escalations")
+
+		return 0
+
+	}
+
+	if !locked {
+
+		return 0
+
+	}
+
+	escalated := 0
+
+	for _, review := range reviews {
+
+		res, err := pullrequest.Escalate(ctx, review)
+
+		if err != nil { // Retried after the next threshold, NO_CANDIDATE usually clears when someone becomes active
+
+			logger.Info("review escalation failed", "pull_request_id", review.PullRequestID, "user_id", review.ReviewerID, "error", err.Error())
+
+			continue
+
+		}
+
+		escalated++
+
+		logger.Info("review escalated", "pull_request_id", review.PullRequestID, "user_id", review.ReviewerID, "reviewers", res.PullRequest.AssignedReviewers)
+
+	}
+
+	return escalated
+
+}
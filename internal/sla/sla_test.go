@@ -0,0 +1,93 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+)
+
+// claimRepo answers reminder claims as an instance with or without the scheduler lock
+type claimRepo struct {
+	repository.Repository
+
+	reviews []models.StaleReview
+
+	locked bool
+
+	err error
+}
+
+func (r *claimRepo) ClaimReminders(_ context.Context, _ time.Duration, _ time.Duration, _ int) ([]models.StaleReview, bool, error) {
+
+	return r.reviews, r.locked, r.err
+
+}
+
+func useRepo(t *testing.T, repo repository.Repository) {
+
+	prev := repository.Default
+
+	repository.Default = repo
+
+	t.Cleanup(func() { repository.Default = prev })
+
+}
+
+func TestRemindLeavesDeliveryToClaim(t *testing.T) {
+
+	review := models.StaleReview{PullRequestID: "pr-1", PullRequestName: "Fix", AuthorID: "u1", ReviewerID: "u2", TeamName: "backend", AgeSeconds: 90000, SLASeconds: 86400}
+
+	useRepo(t, &claimRepo{reviews: []models.StaleReview{review}, locked: true})
+
+	stream, unsubscribe := events.Default.Subscribe("u2")
+
+	defer unsubscribe()
+
+	assert.Equal(t, 1, remind(context.Background(), config.Default().SLA))
+
+	// The claim notifies every instance, publishing here too would remind the reviewer twice
+	select {
+
+	case event := <-stream:
+
+		t.Fatalf("unexpected local reminder %+v", event)
+
+	default:
+
+	}
+
+	event := events.Reminder(review)
+
+	assert.Equal(t, events.ReviewReminder, event.Type)
+
+	assert.Equal(t, "u2", event.UserID)
+
+	assert.Equal(t, "pr-1", event.PullRequest.PullRequestID)
+
+	assert.Equal(t, int64(90000), event.AgeSeconds)
+
+	assert.NotEmpty(t, event.At)
+
+}
+
+func TestRemindSkipsWithoutLock(t *testing.T) {
+
+	review := models.StaleReview{PullRequestID: "pr-1", ReviewerID: "u2"}
+
+	useRepo(t, &claimRepo{reviews: []models.StaleReview{review}, locked: false})
+
+	assert.Equal(t, 0, remind(context.Background(), config.Default().SLA))
+
+	useRepo(t, &claimRepo{err: errors.New("connection refused")})
+
+	assert.Equal(t, 0, remind(context.Background(), config.Default().SLA))
+
+}
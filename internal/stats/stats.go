@@ -0,0 +1,29 @@
+package stats
+
+import (
+	"context"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// Get returns aggregated service statistics
+func Get(ctx context.Context) (models.Stats, error) {
+
+	ctx, span := tracing.Start(ctx, "stats.Get")
+
+	defer span.End()
+
+	res, err := repository.Default.GetStats(ctx)
+
+	if err != nil {
+
+		return models.Stats{}, errs.ErrDatabase
+
+	}
+
+	return res, nil
+
+}
@@ -2,51 +2,57 @@ package team
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
 
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/cache"
-	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/metrics"
 	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
 // Get a team and its members by team name
 func Get(TeamName string, ctx context.Context) (models.Team, error) {
 
-	resTeam, ok := cache.TeamCache.Get(TeamName)
-
-	if !ok {
+	ctx, span := tracing.Start(ctx, "team.Get", attribute.String("team.name", TeamName))
 
-		res, err, ok := database.GetTeamFromDB(ctx, TeamName)
-
-		if err != nil {
+	defer span.End()
 
-			return models.Team{}, errs.ErrDatabase
+	res, err, ok := repository.Default.GetTeam(ctx, TeamName)
 
-		}
-
-		if !ok {
-
-			return models.Team{}, errs.ErrNotFound
+	if err != nil {
 
-		} else {
+		return models.Team{}, errs.ErrDatabase
 
-			cache.TeamCache.Set(TeamName, res)
+	}
 
-			return res, nil
+	if !ok {
 
-		}
+		return models.Team{}, errs.ErrNotFound
 
 	}
 
-	return resTeam.(models.Team), nil
+	return res, nil
 
 }
 
 // Add a team by team name
 func Add(bindedTeam models.Team, ctx context.Context) (models.TeamResponse, error) {
 
-	_, ok := cache.TeamCache.Get(bindedTeam.TeamName)
+	ctx, span := tracing.Start(ctx, "team.Add", attribute.String("team.name", bindedTeam.TeamName), attribute.Int("team.members", len(bindedTeam.Members)))
+
+	defer span.End()
+
+	_, err, ok := repository.Default.GetTeam(ctx, bindedTeam.TeamName)
+
+	if err != nil {
+
+		return models.TeamResponse{}, errs.ErrDatabase
+
+	}
 
 	if ok {
 
@@ -54,104 +60,175 @@ func Add(bindedTeam models.Team, ctx context.Context) (models.TeamResponse, erro
 
 	}
 
-	_, err, ok := database.GetTeamFromDB(ctx, bindedTeam.TeamName)
+	// Members must be new users, moving them silently out of another team is not allowed
+	failure := &errs.ValidationFailure{}
 
-	if err != nil {
+	for i, member := range bindedTeam.Members {
 
-		return models.TeamResponse{}, errs.ErrDatabase
+		user, err, ok := repository.Default.GetUser(ctx, member.UserID)
+
+		if err != nil {
+
+			return models.TeamResponse{}, errs.ErrDatabase
+
+		}
+
+		if ok {
+
+			failure.Details = append(failure.Details, errs.FieldError{
+
+				Field: fmt.Sprintf("members[%d].user_id", i),
+
+				Message: fmt.Sprintf("user already belongs to team %s", user.TeamName),
+			})
+
+		}
 
 	}
 
-	if ok {
+	if len(failure.Details) != 0 {
 
-		return models.TeamResponse{}, errs.ErrTeamExists
+		return models.TeamResponse{}, failure
 
 	}
 
-	cache.TeamCache.Set(bindedTeam.TeamName, bindedTeam)
+	for i := range bindedTeam.Members { // Stored the same way the database defaults them, so the cache agrees
 
-	err = database.SetTeamToDB(ctx, bindedTeam)
+		if bindedTeam.Members[i].Level == "" {
 
-	if err != nil {
+			bindedTeam.Members[i].Level = models.LevelMiddle
 
-		return models.TeamResponse{}, errs.ErrDatabase
+		}
 
 	}
 
-	for _, j := range bindedTeam.Members {
+	// Insert only, a team or member created meanwhile by another request or instance is never overwritten
+	err = repository.Default.CreateTeam(ctx, bindedTeam)
 
-		metrics.UsersCreatedTotal.Inc()
+	var userExists *errs.UserExistsFailure
 
-		newUser := models.User{
+	switch {
 
-			UserID: j.UserID,
+	case errors.Is(err, errs.ErrTeamExists):
+
+		return models.TeamResponse{}, errs.ErrTeamExists
 
-			Username: j.Username,
+	case errors.As(err, &userExists):
 
-			TeamName: bindedTeam.TeamName,
+		for i, member := range bindedTeam.Members {
+
+			if member.UserID == userExists.UserID {
+
+				failure.Details = append(failure.Details, errs.FieldError{Field: fmt.Sprintf("members[%d].user_id", i), Message: "user already belongs to a team"})
+
+			}
 
-			IsActive: j.IsActive,
 		}
 
-		cache.UserCache.Set(j.UserID, newUser)
+		return models.TeamResponse{}, failure
+
+	case err != nil:
+
+		return models.TeamResponse{}, errs.ErrDatabase
 
 	}
 
+	metrics.UsersCreatedTotal.Add(float64(len(bindedTeam.Members)))
+
 	return models.TeamResponse{Team: bindedTeam}, nil
 
 }
 
 func SetActive(bindUser models.UserActivity, ctx context.Context) (models.UserResponse, error) {
 
-	var err error
+	ctx, span := tracing.Start(ctx, "team.SetActive", attribute.String("user.id", bindUser.UserID), attribute.Bool("user.is_active", bindUser.IsActive))
+
+	defer span.End()
+
+	user, err, ok := repository.Default.GetUser(ctx, bindUser.UserID)
+
+	if err != nil {
+
+		return models.UserResponse{}, errs.ErrDatabase
 
-	iUser, ok := cache.UserCache.Get(bindUser.UserID)
+	}
 
 	if !ok {
 
-		iUser, err, ok = database.GetUserFromDB(ctx, bindUser.UserID)
+		return models.UserResponse{}, errs.ErrNoCandidate
 
-		if err != nil {
+	}
 
-			return models.UserResponse{}, errs.ErrDatabase
+	user.IsActive = bindUser.IsActive
 
-		}
+	team, err, ok := repository.Default.GetTeam(ctx, user.TeamName)
+
+	if err != nil || !ok {
 
-		if !ok {
+		return models.UserResponse{}, errs.ErrDatabase
 
-			return models.UserResponse{}, errs.ErrNoCandidate
+	}
+
+	for i, j := range team.Members {
+
+		if j.UserID == bindUser.UserID {
+
+			team.Members[i].IsActive = user.IsActive
+
+			break
 
 		}
 
 	}
 
-	user := iUser.(models.User)
+	_, err = repository.Default.SetTeam(ctx, team)
 
-	user.IsActive = bindUser.IsActive
+	if err != nil {
 
-	cache.UserCache.Set(bindUser.UserID, user)
+		return models.UserResponse{}, errs.ErrDatabase
 
-	iTeam, ok := cache.TeamCache.Get(user.TeamName)
+	}
 
-	if !ok {
+	return models.UserResponse{User: user}, nil
+
+}
 
-		iTeam, err, ok = database.GetTeamFromDB(ctx, user.TeamName)
+// SetProfile replaces the level and skills of a user
+func SetProfile(bindUser models.UserProfile, ctx context.Context) (models.UserResponse, error) {
 
-		if err != nil || !ok {
+	ctx, span := tracing.Start(ctx, "team.SetProfile", attribute.String("user.id", bindUser.UserID), attribute.String("user.level", bindUser.Level))
 
-			return models.UserResponse{}, errs.ErrDatabase
+	defer span.End()
 
-		}
+	user, err, ok := repository.Default.GetUser(ctx, bindUser.UserID)
+
+	if err != nil {
+
+		return models.UserResponse{}, errs.ErrDatabase
+
+	}
+
+	if !ok {
+
+		return models.UserResponse{}, errs.ErrNotFound
 
 	}
 
-	team := iTeam.(models.Team)
+	user.Level, user.Skills = bindUser.Level, bindUser.Skills
+
+	team, err, ok := repository.Default.GetTeam(ctx, user.TeamName)
+
+	if err != nil || !ok {
+
+		return models.UserResponse{}, errs.ErrDatabase
+
+	}
 
 	for i, j := range team.Members {
 
 		if j.UserID == bindUser.UserID {
 
-			team.Members[i].IsActive = user.IsActive
+			team.Members[i].Level, team.Members[i].Skills = user.Level, user.Skills
 
 			break
 
@@ -159,9 +236,7 @@ func SetActive(bindUser models.UserActivity, ctx context.Context) (models.UserRe
 
 	}
 
-	cache.TeamCache.Set(user.TeamName, team)
-
-	err = database.SetTeamToDB(ctx, team)
+	_, err = repository.Default.SetTeam(ctx, team)
 
 	if err != nil {
 
@@ -172,3 +247,70 @@ func SetActive(bindUser models.UserActivity, ctx context.Context) (models.UserRe
 	return models.UserResponse{User: user}, nil
 
 }
+
+// List returns up to limit most recent teams with members
+func List(ctx context.Context, limit int) (models.TeamList, error) {
+
+	ctx, span := tracing.Start(ctx, "team.List", attribute.Int("limit", limit))
+
+	defer span.End()
+
+	teams, err := repository.Default.ListTeams(ctx, limit)
+
+	if err != nil {
+
+		return models.TeamList{}, errs.ErrDatabase
+
+	}
+
+	return models.TeamList{Teams: teams}, nil
+
+}
+
+// GetPolicy returns the review policy of a team, teams without one get zero values meaning the defaults
+func GetPolicy(TeamName string, ctx context.Context) (models.TeamPolicy, error) {
+
+	ctx, span := tracing.Start(ctx, "team.GetPolicy", attribute.String("team.name", TeamName))
+
+	defer span.End()
+
+	if _, err := Get(TeamName, ctx); err != nil {
+
+		return models.TeamPolicy{}, err
+
+	}
+
+	policy, err, _ := repository.Default.GetTeamPolicy(ctx, TeamName)
+
+	if err != nil {
+
+		return models.TeamPolicy{}, errs.ErrDatabase
+
+	}
+
+	return policy, nil
+
+}
+
+// SetPolicy replaces the review policy of an existing team
+func SetPolicy(policy models.TeamPolicy, ctx context.Context) (models.TeamPolicy, error) {
+
+	ctx, span := tracing.Start(ctx, "team.SetPolicy", attribute.String("team.name", policy.TeamName))
+
+	defer span.End()
+
+	if _, err := Get(policy.TeamName, ctx); err != nil {
+
+		return models.TeamPolicy{}, err
+
+	}
+
+	if err := repository.Default.SetTeamPolicy(ctx, policy); err != nil {
+
+		return models.TeamPolicy{}, errs.ErrDatabase
+
+	}
+
+	return policy, nil
+
+}
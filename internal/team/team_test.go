@@ -0,0 +1,73 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+)
+
+// racedRepo sees nothing on reads, like an instance whose cache missed a create made elsewhere,
+// and fails the insert with createErr
+type racedRepo struct {
+	repository.Repository
+
+	createErr error
+}
+
+func (r *racedRepo) GetTeam(_ context.Context, _ string) (models.Team, error, bool) {
+
+	return models.Team{}, nil, false
+
+}
+
+func (r *racedRepo) GetUser(_ context.Context, _ string) (models.User, error, bool) {
+
+	return models.User{}, nil, false
+
+}
+
+func (r *racedRepo) CreateTeam(_ context.Context, _ models.Team) error {
+
+	return r.createErr
+
+}
+
+func useRepo(t *testing.T, repo repository.Repository) {
+
+	prev := repository.Default
+
+	repository.Default = repo
+
+	t.Cleanup(func() { repository.Default = prev })
+
+}
+
+func TestAdd_ConcurrentCreateReturnsConflicts(t *testing.T) {
+
+	ctx := context.Background()
+
+	team := models.Team{TeamName: "backend", Members: []models.TeamMember{{UserID: "u1", Username: "User1"}, {UserID: "u2", Username: "User2"}}}
+
+	useRepo(t, &racedRepo{createErr: &errs.TeamExistsFailure{TeamName: "backend"}})
+
+	_, err := Add(team, ctx)
+
+	assert.ErrorIs(t, err, errs.ErrTeamExists)
+
+	useRepo(t, &racedRepo{createErr: &errs.UserExistsFailure{UserID: "u2"}})
+
+	_, err = Add(team, ctx)
+
+	var failure *errs.ValidationFailure
+
+	assert.True(t, errors.As(err, &failure))
+
+	assert.Equal(t, []errs.FieldError{{Field: "members[1].user_id", Message: "user already belongs to a team"}}, failure.Details)
+
+}
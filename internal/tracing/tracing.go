@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.32.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/config"
+)
+
+// ServiceName is reported in traces unless OTEL_SERVICE_NAME overrides it
+const ServiceName = "pr-service"
+
+// Trace exporters selected by TRACE_EXPORTER
+const (
+	ExporterNone = "none"
+
+	ExporterStdout = "stdout" // pretty printed spans for local debugging
+
+	ExporterOTLP = "otlp" // OTLP over gRPC, endpoint from OTEL_EXPORTER_OTLP_ENDPOINT
+)
+
+var tracer = otel.Tracer("github.com/beganov/Avito-backend-trainee-assignment-autumn-2025")
+
+// Init installs the global tracer provider and W3C propagation, the returned function flushes spans
+// With ExporterNone spans are still created so trace IDs reach logs, but nothing is exported
+func Init(ctx context.Context, cfg config.Trace) (func(context.Context) error, error) {
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	res, err := resource.New(ctx,
+
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+
+		resource.WithFromEnv(), // OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES
+
+		resource.WithTelemetrySDK())
+
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+
+		sdktrace.WithResource(res),
+
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	switch cfg.Exporter {
+
+	case ExporterNone:
+
+	case ExporterStdout:
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+
+		if err != nil {
+
+			return nil, err
+
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+
+	case ExporterOTLP:
+
+		exporter, err := otlptracegrpc.New(ctx)
+
+		if err != nil {
+
+			return nil, err
+
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+
+	default:
+
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+
+}
+
+// Start starts a span for a service call, attributes describe the entity it works on
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+
+}
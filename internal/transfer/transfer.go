@@ -0,0 +1,588 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/database"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	pullrequest "github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/pullRequest"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// Dump encodings
+const (
+	FormatJSON = "json" // a single JSON document
+
+	FormatNDJSON = "ndjson" // a header line followed by one record per line
+)
+
+// CheckFormat validates a format query parameter, json is the default
+func CheckFormat(format string) (string, error) {
+
+	switch format {
+
+	case "":
+
+		return FormatJSON, nil
+
+	case FormatJSON, FormatNDJSON:
+
+		return format, nil
+
+	}
+
+	return "", &errs.ValidationFailure{Details: []errs.FieldError{{Field: "format", Message: "must be json or ndjson"}}}
+
+}
+
+// Export writes a full dump of teams, users, team policies and pull requests to w in the given format
+// NDJSON records are written as the database returns them, a JSON document is assembled in memory first
+// Errors writing to w are returned as is, database errors as errs.ErrDatabase
+func Export(ctx context.Context, w io.Writer, format string) error {
+
+	ctx, span := tracing.Start(ctx, "transfer.Export", attribute.String("export.format", format))
+
+	defer span.End()
+
+	enc := json.NewEncoder(w)
+
+	dump := models.Dump{Teams: []models.DumpTeam{}, Users: []models.User{}, PullRequests: []models.DumpPullRequest{}}
+
+	var writeErr error
+
+	err := repository.Default.Export(ctx, func(record models.DumpRecord) error {
+
+		if format != FormatNDJSON {
+
+			addRecord(&dump, record)
+
+			return nil
+
+		}
+
+		writeErr = enc.Encode(record)
+
+		return writeErr
+
+	})
+
+	if writeErr != nil {
+
+		return writeErr
+
+	}
+
+	if err != nil {
+
+		return errs.ErrDatabase
+
+	}
+
+	if format != FormatNDJSON {
+
+		return Encode(w, dump, format)
+
+	}
+
+	return nil
+
+}
+
+// addRecord adds a decoded record to dump, it reports false for an unknown or empty record
+func addRecord(dump *models.Dump, record models.DumpRecord) bool {
+
+	switch {
+
+	case record.Type == models.RecordHeader:
+
+		dump.Version, dump.ExportedAt = record.Version, record.ExportedAt
+
+	case record.Type == models.RecordTeam && record.Team != nil:
+
+		dump.Teams = append(dump.Teams, *record.Team)
+
+	case record.Type == models.RecordUser && record.User != nil:
+
+		dump.Users = append(dump.Users, *record.User)
+
+	case record.Type == models.RecordTeamPolicy && record.TeamPolicy != nil:
+
+		dump.TeamPolicies = append(dump.TeamPolicies, *record.TeamPolicy)
+
+	case record.Type == models.RecordPullRequest && record.PullRequest != nil:
+
+		dump.PullRequests = append(dump.PullRequests, *record.PullRequest)
+
+	default:
+
+		return false
+
+	}
+
+	return true
+
+}
+
+// Import validates a dump and loads it in one transaction using the conflict policy
+func Import(ctx context.Context, dump models.Dump, policy string) (models.ImportResult, error) {
+
+	ctx, span := tracing.Start(ctx, "transfer.Import", attribute.String("import.policy", policy))
+
+	defer span.End()
+
+	if policy == "" {
+
+		policy = database.ConflictSkip
+
+	}
+
+	if policy != database.ConflictSkip && policy != database.ConflictOverwrite && policy != database.ConflictFail {
+
+		return models.ImportResult{}, &errs.ValidationFailure{Details: []errs.FieldError{{Field: "on_conflict", Message: "must be one of skip, overwrite, fail"}}}
+
+	}
+
+	if details := Validate(dump); len(details) != 0 {
+
+		return models.ImportResult{}, &errs.ValidationFailure{Details: details}
+
+	}
+
+	res, err := repository.Default.Import(ctx, dump, policy)
+
+	if err != nil {
+
+		if errors.Is(err, errs.ErrImportConflict) {
+
+			return models.ImportResult{}, err
+
+		}
+
+		return models.ImportResult{}, errs.ErrDatabase
+
+	}
+
+	return res, nil
+
+}
+
+// Validate checks that a dump is self-contained: users and team policies reference exported teams,
+// pull requests and their history reference exported users and all timestamps are RFC3339
+func Validate(dump models.Dump) []errs.FieldError {
+
+	details := []errs.FieldError{}
+
+	add := func(field string, message string) {
+
+		details = append(details, errs.FieldError{Field: field, Message: message})
+
+	}
+
+	if dump.Version < 1 || dump.Version > models.DumpVersion { // older versions only lack sections
+
+		add("version", fmt.Sprintf("unsupported version %d, expected 1 to %d", dump.Version, models.DumpVersion))
+
+		return details // other fields may mean something else in another version
+
+	}
+
+	teams := make(map[string]bool, len(dump.Teams))
+
+	for i, team := range dump.Teams {
+
+		field := fmt.Sprintf("teams[%d].team_name", i)
+
+		switch {
+
+		case team.TeamName == "":
+
+			add(field, "is required")
+
+		case teams[team.TeamName]:
+
+			add(field, "must be unique")
+
+		}
+
+		teams[team.TeamName] = true
+
+	}
+
+	users := make(map[string]bool, len(dump.Users))
+
+	for i, user := range dump.Users {
+
+		field := fmt.Sprintf("users[%d]", i)
+
+		switch {
+
+		case user.UserID == "":
+
+			add(field+".user_id", "is required")
+
+		case users[user.UserID]:
+
+			add(field+".user_id", "must be unique")
+
+		}
+
+		users[user.UserID] = true
+
+		if user.Username == "" {
+
+			add(field+".username", "is required")
+
+		}
+
+		if !teams[user.TeamName] {
+
+			add(field+".team_name", "must reference an exported team")
+
+		}
+
+		switch user.Level {
+
+		case "", models.LevelJunior, models.LevelMiddle, models.LevelSenior: // missing in dumps made before levels, imported as middle
+
+		default:
+
+			add(field+".level", "must be junior, middle or senior")
+
+		}
+
+	}
+
+	policies := make(map[string]bool, len(dump.TeamPolicies))
+
+	for i, policy := range dump.TeamPolicies {
+
+		field := fmt.Sprintf("team_policies[%d]", i)
+
+		switch {
+
+		case !teams[policy.TeamName]:
+
+			add(field+".team_name", "must reference an exported team")
+
+		case policies[policy.TeamName]:
+
+			add(field+".team_name", "must be unique")
+
+		}
+
+		policies[policy.TeamName] = true
+
+		if policy.ReviewSLAHours < 0 || policy.EscalateAfterHours < 0 || policy.MaxEscalations < 0 || policy.MinSeniorReviewers < 0 {
+
+			add(field, "must not have negative values")
+
+		}
+
+		for j, label := range policy.SkillLabels {
+
+			if label == "" {
+
+				add(fmt.Sprintf("%s.skill_labels[%d]", field, j), "is required")
+
+			}
+
+		}
+
+	}
+
+	prs := make(map[string]bool, len(dump.PullRequests))
+
+	for i, pr := range dump.PullRequests {
+
+		field := fmt.Sprintf("pull_requests[%d]", i)
+
+		switch {
+
+		case pr.PullRequestID == "":
+
+			add(field+".pull_request_id", "is required")
+
+		case prs[pr.PullRequestID]:
+
+			add(field+".pull_request_id", "must be unique")
+
+		}
+
+		prs[pr.PullRequestID] = true
+
+		if pr.PullRequestName == "" {
+
+			add(field+".pull_request_name", "is required")
+
+		}
+
+		if !users[pr.AuthorID] {
+
+			add(field+".author_id", "must reference an exported user")
+
+		}
+
+		if pr.Status != pullrequest.OpenStatus && pr.Status != pullrequest.MergeStatus {
+
+			add(field+".status", "must be OPEN or MERGED")
+
+		}
+
+		switch pr.Priority {
+
+		case "", pullrequest.PriorityLow, pullrequest.PriorityNormal, pullrequest.PriorityHigh, pullrequest.PriorityUrgent:
+
+		default:
+
+			add(field+".priority", "must be low, normal, high or urgent")
+
+		}
+
+		if !validTime(pr.CreatedAt, true) {
+
+			add(field+".createdAt", "must be an RFC3339 timestamp")
+
+		}
+
+		if !validTime(pr.MergedAt, true) {
+
+			add(field+".mergedAt", "must be an RFC3339 timestamp")
+
+		}
+
+		reviewers := make(map[string]bool, len(pr.Reviewers))
+
+		for j, reviewer := range pr.Reviewers {
+
+			field := fmt.Sprintf("%s.reviewers[%d]", field, j)
+
+			switch {
+
+			case !users[reviewer.UserID]:
+
+				add(field+".user_id", "must reference an exported user")
+
+			case reviewers[reviewer.UserID]:
+
+				add(field+".user_id", "must be unique")
+
+			}
+
+			reviewers[reviewer.UserID] = true
+
+			if reviewer.State != database.ReviewerAssigned && reviewer.State != database.ReviewerUnassigned {
+
+				add(field+".state", "must be ASSIGNED or UNASSIGNED")
+
+			}
+
+			if !validTime(reviewer.AssignedAt, false) {
+
+				add(field+".assigned_at", "must be an RFC3339 timestamp")
+
+			}
+
+			if reviewer.AssignedBy == "" {
+
+				add(field+".assigned_by", "is required")
+
+			}
+
+		}
+
+		for j, entry := range pr.History {
+
+			field := fmt.Sprintf("%s.history[%d]", field, j)
+
+			switch entry.Action {
+
+			case pullrequest.HistoryReassigned, pullrequest.HistoryEscalated, pullrequest.HistoryDeclined:
+
+			default:
+
+				add(field+".action", "must be reassigned, escalated or declined")
+
+			}
+
+			if !users[entry.UserID] {
+
+				add(field+".user_id", "must reference an exported user")
+
+			}
+
+			if entry.ReplacedBy != "" && !users[entry.ReplacedBy] {
+
+				add(field+".replaced_by", "must reference an exported user")
+
+			}
+
+			if !validTime(entry.At, false) {
+
+				add(field+".at", "must be an RFC3339 timestamp")
+
+			}
+
+		}
+
+	}
+
+	return details
+
+}
+
+func validTime(value string, optional bool) bool {
+
+	if value == "" {
+
+		return optional
+
+	}
+
+	_, err := time.Parse(time.RFC3339, value)
+
+	return err == nil
+
+}
+
+// Encode writes a dump in the given format
+func Encode(w io.Writer, dump models.Dump, format string) error {
+
+	enc := json.NewEncoder(w)
+
+	if format != FormatNDJSON {
+
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(dump)
+
+	}
+
+	err := enc.Encode(models.DumpRecord{Type: models.RecordHeader, Version: dump.Version, ExportedAt: dump.ExportedAt})
+
+	for i := 0; err == nil && i < len(dump.Teams); i++ {
+
+		err = enc.Encode(models.DumpRecord{Type: models.RecordTeam, Team: &dump.Teams[i]})
+
+	}
+
+	for i := 0; err == nil && i < len(dump.Users); i++ {
+
+		err = enc.Encode(models.DumpRecord{Type: models.RecordUser, User: &dump.Users[i]})
+
+	}
+
+	for i := 0; err == nil && i < len(dump.TeamPolicies); i++ {
+
+		err = enc.Encode(models.DumpRecord{Type: models.RecordTeamPolicy, TeamPolicy: &dump.TeamPolicies[i]})
+
+	}
+
+	for i := 0; err == nil && i < len(dump.PullRequests); i++ {
+
+		err = enc.Encode(models.DumpRecord{Type: models.RecordPullRequest, PullRequest: &dump.PullRequests[i]})
+
+	}
+
+	return err
+
+}
+
+// Decode reads a dump in the given format, malformed input is reported as a validation error
+func Decode(r io.Reader, format string) (models.Dump, error) {
+
+	var dump models.Dump
+
+	if format != FormatNDJSON {
+
+		if err := json.NewDecoder(r).Decode(&dump); err != nil {
+
+			return models.Dump{}, decodeError("body", err)
+
+		}
+
+		return dump, nil
+
+	}
+
+	scanner := bufio.NewScanner(r)
+
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // a pull request with its history fits in one line
+
+	line := 0
+
+	header := false
+
+	for scanner.Scan() {
+
+		line++
+
+		if len(scanner.Bytes()) == 0 {
+
+			continue
+
+		}
+
+		var record models.DumpRecord
+
+		field := fmt.Sprintf("line %d", line)
+
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+
+			return models.Dump{}, decodeError(field, err)
+
+		}
+
+		switch {
+
+		case record.Type == models.RecordHeader && !header:
+
+			header = true
+
+			addRecord(&dump, record)
+
+		case !header:
+
+			return models.Dump{}, decodeError(field, errors.New("first record must be the header"))
+
+		case record.Type == models.RecordHeader || !addRecord(&dump, record):
+
+			return models.Dump{}, decodeError(field, fmt.Errorf("unknown record type %q", record.Type))
+
+		}
+
+	}
+
+	if err := scanner.Err(); err != nil {
+
+		return models.Dump{}, decodeError(fmt.Sprintf("line %d", line+1), err)
+
+	}
+
+	return dump, nil
+
+}
+
+func decodeError(field string, err error) error {
+
+	var tooLarge *http.MaxBytesError
+
+	if errors.As(err, &tooLarge) { // Body cut by the route body limit
+
+		return errs.ErrPayloadTooLarge
+
+	}
+
+	return &errs.ValidationFailure{Details: []errs.FieldError{{Field: field, Message: err.Error()}}}
+
+}
@@ -0,0 +1,241 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/repository"
+)
+
+func testDump() models.Dump {
+
+	return models.Dump{
+
+		Version: models.DumpVersion,
+
+		ExportedAt: "2025-12-01T10:00:00Z",
+
+		Teams: []models.DumpTeam{{TeamName: "backend"}},
+
+		Users: []models.User{
+
+			{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+
+			{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+
+			{UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: true},
+		},
+
+		TeamPolicies: []models.TeamPolicy{{TeamName: "backend", ReviewSLAHours: 24, EscalateAfterHours: 48, MaxEscalations: 2, SkillLabels: []string{"db"}}},
+
+		PullRequests: []models.DumpPullRequest{{
+
+			PullRequestID: "pr-1",
+
+			PullRequestName: "Add search",
+
+			AuthorID: "u1",
+
+			Status: "OPEN",
+
+			CreatedAt: "2025-12-01T09:00:00Z",
+
+			Reviewers: []models.DumpReviewer{{UserID: "u2", Slot: 0, State: "ASSIGNED", AssignedAt: "2025-12-01T09:00:00Z", AssignedBy: "system"}},
+
+			History: []models.PRHistoryEntry{{Action: "declined", UserID: "u3", ReplacedBy: "u2", Reason: "on vacation", At: "2025-12-01T09:00:00Z"}},
+		}},
+	}
+
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+
+	for _, format := range []string{FormatJSON, FormatNDJSON} {
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, Encode(&buf, testDump(), format))
+
+		got, err := Decode(&buf, format)
+
+		assert.NoError(t, err, format)
+
+		assert.Equal(t, testDump(), got, format)
+
+		assert.Empty(t, Validate(got), format)
+
+	}
+
+}
+
+func TestDecode_NDJSONRequiresHeader(t *testing.T) {
+
+	_, err := Decode(strings.NewReader(`{"type":"team","team":{"team_name":"backend"}}`), FormatNDJSON)
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	_, err = Decode(strings.NewReader("{\"type\":\"header\",\"version\":1}\n{\"type\":\"comment\"}\n"), FormatNDJSON)
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+}
+
+func TestValidate_References(t *testing.T) {
+
+	dump := testDump()
+
+	dump.Users[1].TeamName = "frontend"
+
+	dump.PullRequests[0].Status = "CLOSED"
+
+	dump.PullRequests[0].Reviewers[0].UserID = "u9"
+
+	dump.PullRequests[0].Reviewers[0].AssignedAt = "yesterday"
+
+	dump.TeamPolicies[0].TeamName = "frontend"
+
+	dump.PullRequests[0].History[0].UserID = "u9"
+
+	assert.ElementsMatch(t, []errs.FieldError{
+
+		{Field: "users[1].team_name", Message: "must reference an exported team"},
+
+		{Field: "pull_requests[0].status", Message: "must be OPEN or MERGED"},
+
+		{Field: "pull_requests[0].reviewers[0].user_id", Message: "must reference an exported user"},
+
+		{Field: "pull_requests[0].reviewers[0].assigned_at", Message: "must be an RFC3339 timestamp"},
+
+		{Field: "team_policies[0].team_name", Message: "must reference an exported team"},
+
+		{Field: "pull_requests[0].history[0].user_id", Message: "must reference an exported user"},
+	}, Validate(dump))
+
+	dump.Version = 3
+
+	assert.Equal(t, []errs.FieldError{{Field: "version", Message: "unsupported version 3, expected 1 to 2"}}, Validate(dump))
+
+}
+
+func TestValidate_AcceptsVersion1(t *testing.T) {
+
+	dump := testDump()
+
+	dump.Version, dump.TeamPolicies, dump.PullRequests[0].History = 1, nil, nil // Sections added in version 2
+
+	assert.Empty(t, Validate(dump))
+
+}
+
+// streamRepo emits the records of a dump one by one like the database does
+type streamRepo struct {
+	repository.Repository
+
+	dump models.Dump
+
+	emitted int
+}
+
+func (r *streamRepo) Export(_ context.Context, emit func(models.DumpRecord) error) error {
+
+	records := []models.DumpRecord{{Type: models.RecordHeader, Version: r.dump.Version, ExportedAt: r.dump.ExportedAt}}
+
+	for i := range r.dump.Teams {
+
+		records = append(records, models.DumpRecord{Type: models.RecordTeam, Team: &r.dump.Teams[i]})
+
+	}
+
+	for i := range r.dump.Users {
+
+		records = append(records, models.DumpRecord{Type: models.RecordUser, User: &r.dump.Users[i]})
+
+	}
+
+	for i := range r.dump.TeamPolicies {
+
+		records = append(records, models.DumpRecord{Type: models.RecordTeamPolicy, TeamPolicy: &r.dump.TeamPolicies[i]})
+
+	}
+
+	for i := range r.dump.PullRequests {
+
+		records = append(records, models.DumpRecord{Type: models.RecordPullRequest, PullRequest: &r.dump.PullRequests[i]})
+
+	}
+
+	for _, record := range records {
+
+		if err := emit(record); err != nil {
+
+			return err
+
+		}
+
+		r.emitted++
+
+	}
+
+	return nil
+
+}
+
+// failingWriter accepts n writes and fails the rest, like a client that went away
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+
+	if w.n == 0 {
+
+		return 0, errors.New("connection reset")
+
+	}
+
+	w.n--
+
+	return len(p), nil
+
+}
+
+func TestExport_StreamsRecords(t *testing.T) {
+
+	prev := repository.Default
+
+	t.Cleanup(func() { repository.Default = prev })
+
+	for _, format := range []string{FormatJSON, FormatNDJSON} {
+
+		repository.Default = &streamRepo{dump: testDump()}
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, Export(context.Background(), &buf, format))
+
+		got, err := Decode(&buf, format)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, testDump(), got, format)
+
+	}
+
+	repo := &streamRepo{dump: testDump()}
+
+	repository.Default = repo
+
+	err := Export(context.Background(), &failingWriter{n: 2}, FormatNDJSON)
+
+	assert.EqualError(t, err, "connection reset")
+
+	assert.Equal(t, 2, repo.emitted, "reading stops once the client is gone")
+
+}
@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// Validator validates requests using `validate` struct tags, it is shared by every transport
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New creates a validator with the custom rules registered
+func New() *Validator {
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON names so details match the API schema
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if name == "-" {
+
+			return ""
+
+		}
+
+		return name
+
+	})
+
+	_ = validate.RegisterValidation("notblank", validators.NotBlank) // Reject whitespace-only identifiers
+
+	validate.RegisterStructValidation(uniqueMembers, models.Team{})
+
+	return &Validator{validate: validate}
+
+}
+
+// uniqueMembers reports every repeated member user_id in a team
+func uniqueMembers(sl validator.StructLevel) {
+
+	team := sl.Current().Interface().(models.Team)
+
+	seen := make(map[string]bool, len(team.Members))
+
+	for i, member := range team.Members {
+
+		if seen[member.UserID] {
+
+			sl.ReportError(member.UserID, fmt.Sprintf("members[%d].user_id", i), "UserID", "unique", "")
+
+		}
+
+		seen[member.UserID] = true
+
+	}
+
+}
+
+// Validate implements echo.Validator, failures are returned as *errs.ValidationFailure
+func (v *Validator) Validate(i interface{}) error {
+
+	err := v.validate.Struct(i)
+
+	var fieldErrs validator.ValidationErrors
+
+	if !errors.As(err, &fieldErrs) {
+
+		return err
+
+	}
+
+	failure := &errs.ValidationFailure{}
+
+	for _, fe := range fieldErrs {
+
+		// Namespace starts with the struct name, drop it to keep the JSON path
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+
+		failure.Details = append(failure.Details, errs.FieldError{Field: field, Message: fieldMessage(fe)})
+
+	}
+
+	return failure
+
+}
+
+// fieldMessage renders a human readable message for a failed rule
+func fieldMessage(fe validator.FieldError) string {
+
+	switch fe.Tag() {
+
+	case "required":
+
+		return "is required"
+
+	case "notblank":
+
+		return "must not be blank"
+
+	case "max":
+
+		if fe.Kind() == reflect.Slice {
+
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+
+		}
+
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+
+	case "min":
+
+		return fmt.Sprintf("must contain at least %s items", fe.Param())
+
+	case "unique":
+
+		return "must be unique"
+
+	case "oneof":
+
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+
+	}
+
+	return fmt.Sprintf("failed %s validation", fe.Tag())
+
+}
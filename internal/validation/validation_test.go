@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/errs"
+	"github.com/beganov/Avito-backend-trainee-assignment-autumn-2025/internal/models"
+)
+
+// details returns the field errors carried by a validation failure
+func details(err error) []errs.FieldError {
+
+	var failure *errs.ValidationFailure
+
+	if !errors.As(err, &failure) {
+
+		return nil
+
+	}
+
+	return failure.Details
+
+}
+
+func TestValidator_FieldDetails(t *testing.T) {
+
+	v := New()
+
+	err := v.Validate(&models.PullRequestShort{PullRequestID: "", PullRequestName: "feature", AuthorID: "   "})
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	assert.ElementsMatch(t, []errs.FieldError{
+
+		{Field: "pull_request_id", Message: "is required"},
+
+		{Field: "author_id", Message: "must not be blank"},
+	}, details(err))
+
+}
+
+func TestValidator_TeamMembers(t *testing.T) {
+
+	v := New()
+
+	err := v.Validate(&models.Team{
+
+		TeamName: "backend",
+
+		Members: []models.TeamMember{
+
+			{UserID: "u1", Username: "User1"},
+
+			{UserID: "u1", Username: "User1 again"},
+
+			{UserID: "u2"},
+		},
+	})
+
+	assert.ErrorIs(t, err, errs.ErrValidation)
+
+	assert.ElementsMatch(t, []errs.FieldError{
+
+		{Field: "members[1].user_id", Message: "must be unique"},
+
+		{Field: "members[2].username", Message: "is required"},
+	}, details(err))
+
+	err = v.Validate(&models.Team{TeamName: "backend"})
+
+	assert.Equal(t, []errs.FieldError{{Field: "members", Message: "is required"}}, details(err))
+
+	assert.NoError(t, v.Validate(&models.Team{TeamName: "backend", Members: []models.TeamMember{{UserID: "u1", Username: "User1"}}}))
+
+}
+
+func TestValidator_PriorityAndLabels(t *testing.T) {
+
+	v := New()
+
+	pr := models.PullRequestShort{PullRequestID: "pr1", PullRequestName: "feature", AuthorID: "u1", Priority: "asap", Labels: []string{"backend", " "}}
+
+	assert.ElementsMatch(t, []errs.FieldError{
+
+		{Field: "priority", Message: "must be one of low, normal, high, urgent"},
+
+		{Field: "labels[1]", Message: "must not be blank"},
+	}, details(v.Validate(&pr)))
+
+	pr.Priority, pr.Labels = "", nil // Both are optional
+
+	assert.NoError(t, v.Validate(&pr))
+
+}
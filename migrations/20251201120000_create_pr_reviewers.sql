@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    slot SMALLINT NOT NULL,
+    state VARCHAR(16) NOT NULL DEFAULT 'ASSIGNED',
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    assigned_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    PRIMARY KEY (pull_request_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS pr_reviewers_user_state_idx ON pr_reviewers (user_id, state);
+
+-- Backfill reviewers from the JSONB column, keeping array order as slot
+INSERT INTO pr_reviewers (pull_request_id, user_id, slot, state, assigned_at, assigned_by)
+SELECT pr.pull_request_id, r.user_id, r.ord - 1, 'ASSIGNED', COALESCE(pr.created_at, NOW()), 'backfill'
+FROM pull_requests pr
+CROSS JOIN LATERAL jsonb_array_elements_text(pr.assigned_reviewers) WITH ORDINALITY AS r(user_id, ord)
+WHERE EXISTS (SELECT 1 FROM users u WHERE u.user_id = r.user_id)
+ON CONFLICT (pull_request_id, user_id) DO NOTHING;
+
+ALTER TABLE pull_requests DROP COLUMN assigned_reviewers;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests ADD COLUMN assigned_reviewers JSONB NOT NULL DEFAULT '[]';
+
+UPDATE pull_requests pr SET assigned_reviewers = r.reviewers
+FROM (
+    SELECT pull_request_id, jsonb_agg(user_id ORDER BY slot) AS reviewers
+    FROM pr_reviewers
+    WHERE state = 'ASSIGNED'
+    GROUP BY pull_request_id
+) r
+WHERE r.pull_request_id = pr.pull_request_id;
+
+DROP TABLE pr_reviewers;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS team_policies (
+    team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    review_sla_seconds BIGINT NOT NULL DEFAULT 0
+);
+
+-- Last SLA reminder sent to the reviewer, NULL until the review becomes overdue
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS pr_reviewers_state_assigned_idx ON pr_reviewers (state, assigned_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS pr_reviewers_state_assigned_idx;
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS reminded_at;
+
+DROP TABLE IF EXISTS team_policies;
+-- +goose StatementEnd
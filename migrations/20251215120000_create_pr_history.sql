@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE team_policies
+    ADD COLUMN IF NOT EXISTS escalate_after_seconds BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_escalations INTEGER NOT NULL DEFAULT 0;
+
+-- Last automatic escalation attempt, NULL until the review is overdue for escalation
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS pr_history (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    action VARCHAR(32) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    replaced_by VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS pr_history_pr_action_idx ON pr_history (pull_request_id, action);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pr_history;
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS escalated_at;
+
+ALTER TABLE team_policies
+    DROP COLUMN IF EXISTS escalate_after_seconds,
+    DROP COLUMN IF EXISTS max_escalations;
+-- +goose StatementEnd
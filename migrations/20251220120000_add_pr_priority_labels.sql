@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(16) NOT NULL DEFAULT 'normal'
+        CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    ADD COLUMN IF NOT EXISTS labels VARCHAR(50)[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS pull_requests_labels_idx ON pull_requests USING GIN (labels);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS pull_requests_labels_idx;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS labels;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS level VARCHAR(16) NOT NULL DEFAULT 'middle'
+        CHECK (level IN ('junior', 'middle', 'senior')),
+    ADD COLUMN IF NOT EXISTS skills VARCHAR(50)[] NOT NULL DEFAULT '{}';
+
+-- Reviewer requirements of a team, checked on every assignment
+ALTER TABLE team_policies
+    ADD COLUMN IF NOT EXISTS min_senior_reviewers INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS skill_labels VARCHAR(50)[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE team_policies
+    DROP COLUMN IF EXISTS min_senior_reviewers,
+    DROP COLUMN IF EXISTS skill_labels;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS level,
+    DROP COLUMN IF EXISTS skills;
+-- +goose StatementEnd